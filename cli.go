@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/annicaburns/learngo/codec"
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goConcurrency"
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/httpapi"
+	"github.com/annicaburns/learngo/importer"
+	"github.com/annicaburns/learngo/registry"
+	"github.com/annicaburns/learngo/rpcapi"
+	"github.com/annicaburns/learngo/store"
+)
+
+// Exit codes returned by the learngo command
+const (
+	exitOK      = 0 // everything worked
+	exitFailure = 1 // the demo itself failed
+	exitUsage   = 2 // the command line was wrong
+)
+
+// command is a single learngo subcommand such as "run" or "list"
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+// commands is filled in by init because helpCommand refers back to it
+var commands []command
+
+func init() {
+	commands = []command{
+		{"list", "learngo list [-v] [prefix]", "list every demo, or only those starting with prefix", listCommand},
+		{"run", "learngo run <package.Demo> [flags]", "run a single demo by name", runCommand},
+		{"serve", "learngo serve [-addr host:port] [-rpc host:port]", "serve the greeting JSON and RPC APIs until interrupted", serveCommand},
+		{"import", "learngo import [-roster file] [-dry-run] [-update] <file>", "check a CSV (or JSON, XML, key: value) roster and add it to a store", importCommand},
+		{"salutations", "learngo salutations query [-roster file] [-format name] <query>", "select salutations with a query such as 'name ~ ^Ma sort by name'", salutationsCommand},
+		{"help", "learngo help [command | package.Demo]", "show help for learngo, one of its commands or a demo", helpCommand},
+	}
+}
+
+// run dispatches to a subcommand and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+	switch name := args[0]; name {
+	case "-h", "-help", "--help":
+		printUsage(stdout)
+		return exitOK
+	default:
+		if cmd, exists := findCommand(name); exists {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+		fmt.Fprintf(stderr, "learngo: unknown command %q\n\n", name)
+		printUsage(stderr)
+		return exitUsage
+	}
+}
+
+func findCommand(name string) (cmd command, exists bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "learngo runs the samples from The Go Programming Language course by name.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-40s %s\n", c.usage, c.summary)
+	}
+}
+
+// newFlagSet creates a flag set whose errors and --help output go to stderr
+func newFlagSet(cmd command, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s\n\n%s\n", cmd.usage, cmd.summary)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseExitCode turns a flag parsing error into an exit code - asking for help is not a failure
+func parseExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitUsage
+}
+
+func listCommand(args []string, stdout, stderr io.Writer) int {
+	cmd, _ := findCommand("list")
+	flags := newFlagSet(cmd, stderr)
+	verbose := flags.Bool("v", false, "also show each demo's description and parameters")
+	if err := flags.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	prefix := flags.Arg(0)
+	for _, demo := range registry.List() {
+		if !strings.HasPrefix(demo.Name, prefix) {
+			continue
+		}
+		if !*verbose {
+			fmt.Fprintln(stdout, demo.Name)
+			continue
+		}
+		fmt.Fprintf(stdout, "%-36s %s\n", demo.Name, demo.Description)
+		for _, param := range demo.Params {
+			fmt.Fprintf(stdout, "    -%-10s %-6s %s (default %q)\n", param.Name, param.Kind, param.Description, param.Default)
+		}
+	}
+	return exitOK
+}
+
+// paramFlag lets a demo parameter be set from the command line
+type paramFlag struct {
+	param  registry.Param
+	values map[string]string
+}
+
+func (f *paramFlag) String() string {
+	return f.param.Default
+}
+
+func (f *paramFlag) Set(value string) error {
+	f.values[f.param.Name] = value
+	return nil
+}
+
+// IsBoolFlag allows a bool parameter to be switched on with just -isFormal
+func (f *paramFlag) IsBoolFlag() bool {
+	return f.param.Kind == registry.Bool
+}
+
+func runCommand(args []string, stdout, stderr io.Writer) int {
+	cmd, _ := findCommand("run")
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		flags := newFlagSet(cmd, stderr)
+		if err := flags.Parse(args); err != nil {
+			return parseExitCode(err)
+		}
+		fmt.Fprintln(stderr, "learngo run: missing demo name")
+		flags.Usage()
+		return exitUsage
+	}
+
+	name := args[0]
+	demo, exists := registry.Lookup(name)
+	if !exists {
+		fmt.Fprintf(stderr, "learngo run: unknown demo %q - use \"learngo list\" to see them all\n", name)
+		return exitUsage
+	}
+	// Each demo gets flags for exactly the parameters in its schema
+	flags := newFlagSet(command{name: name, usage: "learngo run " + name + " [flags]", summary: demo.Description}, stderr)
+	values := make(map[string]string)
+	for _, param := range demo.Params {
+		flags.Var(&paramFlag{param, values}, param.Name, param.Description)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return parseExitCode(err)
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "learngo run: unexpected arguments %q\n", flags.Args())
+		flags.Usage()
+		return exitUsage
+	}
+
+	if err := registry.Run(name, stdout, values); err != nil {
+		fmt.Fprintf(stderr, "learngo run: %v\n", err)
+		if errors.Is(err, registry.ErrInvalidParam) {
+			return exitUsage
+		}
+		return exitFailure
+	}
+	return exitOK
+}
+
+func serveCommand(args []string, stdout, stderr io.Writer) int {
+	cmd, _ := findCommand("serve")
+	flags := newFlagSet(cmd, stderr)
+	addr := flags.String("addr", "localhost:8080", "address to serve the JSON API on")
+	rpcAddr := flags.String("rpc", "", "address to serve the net/rpc Greeter on (empty doesn't serve it)")
+	if err := flags.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	// if either server fails, the group cancels ctx and the other one shuts down too
+	group, ctx := goConcurrency.GroupWithContext(ctx)
+	fmt.Fprintf(stdout, "serving the greeting API on http://%s (Ctrl+C to stop)\n", *addr)
+	group.Go(func() error { return httpapi.ListenAndServe(ctx, *addr, httpapi.NewServer()) })
+	if *rpcAddr != "" {
+		fmt.Fprintf(stdout, "serving the Greeter RPC service on %s\n", *rpcAddr)
+		group.Go(func() error { return rpcapi.ListenAndServe(ctx, *rpcAddr) })
+	}
+	if err := group.Wait(); err != nil {
+		fmt.Fprintf(stderr, "learngo serve: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+func importCommand(args []string, stdout, stderr io.Writer) int {
+	cmd, _ := findCommand("import")
+	flags := newFlagSet(cmd, stderr)
+	roster := flags.String("roster", "roster.json", "store file to import into - .json for a JSON roster, anything else for a key/value log")
+	format := flags.String("format", "", "format of the file: "+strings.Join(codec.Names(), ", ")+" (empty picks it from the extension)")
+	var options importer.Options
+	flags.BoolVar(&options.DryRun, "dry-run", false, "check the file and report what would change without writing anything")
+	flags.BoolVar(&options.Update, "update", false, "replace salutations already in the store instead of reporting them")
+	flags.BoolVar(&options.SkipInvalid, "skip-invalid", false, "import the valid records even when others have problems")
+	if err := flags.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	path := flags.Arg(0)
+
+	var fileCodec codec.Codec
+	var err error
+	if *format != "" {
+		fileCodec, err = codec.Lookup(*format)
+	} else {
+		fileCodec, err = codec.ForPath(path)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "learngo import: %v\n", err)
+		return exitUsage
+	}
+	// "-" reads the roster from standard input, as long as -format says what it is
+	input := io.Reader(os.Stdin)
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "learngo import: %v\n", err)
+			return exitFailure
+		}
+		defer file.Close()
+		input = file
+	}
+	var into store.SalutationStore
+	if _, statErr := os.Stat(*roster); options.DryRun && errors.Is(statErr, fs.ErrNotExist) {
+		// a dry run has nothing to compare against, and mustn't leave a new empty store behind
+		into = store.NewMemoryStore()
+	} else {
+		into, err = store.Open(*roster)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "learngo import: %v\n", err)
+		return exitFailure
+	}
+
+	report, err := importer.Import(fileCodec, input, into, options)
+	if closeErr := into.Close(); err == nil {
+		err = closeErr
+	}
+	for _, problem := range report.Problems {
+		fmt.Fprintf(stderr, "%s: %v\n", path, problem)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "learngo import: %v\n", err)
+		return exitFailure
+	}
+	fmt.Fprintf(stdout, "%d records read from %s: %d new, %d updates, %d with problems\n",
+		report.Records, path, report.Created, report.Updated, len(report.Problems))
+	switch {
+	case report.Written:
+		fmt.Fprintf(stdout, "%d salutations written to %s\n", report.Created+report.Updated, *roster)
+	case options.DryRun:
+		fmt.Fprintln(stdout, "dry run - nothing was written")
+	default:
+		fmt.Fprintln(stdout, "nothing was written - fix the problems, or import the rest with -skip-invalid")
+	}
+	if len(report.Problems) > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func salutationsCommand(args []string, stdout, stderr io.Writer) int {
+	cmd, _ := findCommand("salutations")
+	flags := newFlagSet(cmd, stderr)
+	roster := flags.String("roster", "", "roster file (.json or .kv) to query instead of the built in salutations")
+	format := flags.String("format", "", "write the results as "+strings.Join(codec.Names(), ", ")+" (empty prints a table)")
+	if len(args) == 0 || args[0] != "query" {
+		if err := flags.Parse(args); err != nil {
+			return parseExitCode(err)
+		}
+		fmt.Fprintln(stderr, "learngo salutations: the only subcommand is query")
+		flags.Usage()
+		return exitUsage
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return parseExitCode(err)
+	}
+	// the query can be one quoted argument or several words
+	text := strings.Join(flags.Args(), " ")
+
+	var output codec.Codec
+	if *format != "" {
+		var err error
+		if output, err = codec.Lookup(*format); err != nil {
+			fmt.Fprintf(stderr, "learngo salutations: %v\n", err)
+			return exitUsage
+		}
+	}
+	query, err := goInterfaces.ParseQuery(text)
+	if err != nil {
+		fmt.Fprintf(stderr, "learngo salutations: %v\n", err)
+		var queryError *goInterfaces.QueryError
+		if errors.As(err, &queryError) {
+			// point at the mistake - Position counts bytes, but the terminal shows characters
+			fmt.Fprintf(stderr, "  %s\n  %s^\n", text, strings.Repeat(" ", utf8.RuneCountInString(text[:queryError.Position])))
+		}
+		return exitUsage
+	}
+	salutations, err := store.LoadRoster(*roster, core.VendSalutations())
+	if err != nil {
+		fmt.Fprintf(stderr, "learngo salutations: %v\n", err)
+		return exitFailure
+	}
+
+	result := query.Apply(salutations.ToInterfaces())
+	if output != nil {
+		err = codec.WriteAll(output, stdout, core.FromSalutations(result))
+	} else {
+		table := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "NAME\tCASUAL\tFORMAL")
+		for _, s := range result {
+			fmt.Fprintf(table, "%s\t%s\t%s\n", s.Name, s.CasualGreeting, s.FormalGreeting)
+		}
+		err = table.Flush()
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "learngo salutations: %v\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+func helpCommand(args []string, stdout, stderr io.Writer) int {
+	switch len(args) {
+	case 0:
+		printUsage(stdout)
+		return exitOK
+	case 1:
+		if strings.HasPrefix(args[0], "-") {
+			// "learngo help help" ends up here asking for help on help
+			printUsage(stdout)
+			return exitOK
+		}
+		if cmd, exists := findCommand(args[0]); exists {
+			return cmd.run([]string{"-help"}, stdout, stdout)
+		}
+		// "learngo help goLoops.BasicForLoop" shows the flags that demo takes
+		if _, exists := registry.Lookup(args[0]); exists {
+			return runCommand([]string{args[0], "-help"}, stdout, stdout)
+		}
+		fmt.Fprintf(stderr, "learngo help: unknown command or demo %q\n", args[0])
+		return exitUsage
+	default:
+		cmd, _ := findCommand("help")
+		fmt.Fprintf(stderr, "Usage: %s\n", cmd.usage)
+		return exitUsage
+	}
+}
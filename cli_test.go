@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/annicaburns/learngo/registry"
+)
+
+// The demos below are only registered in the test binary, under a prefix no real package uses
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "clitest.Echo",
+		Description: "write back the parameters",
+		Params:      []registry.Param{registry.NameParam, registry.TimesParam, registry.IsFormalParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			_, err := fmt.Fprintf(w, "%s %d %t\n", args.String("name"), args.Int("times"), args.Bool("isFormal"))
+			return err
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "clitest.Fail",
+		Description: "always fail",
+		Run:         func(io.Writer, registry.Args) error { return errors.New("it went wrong") },
+	})
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args   []string
+		code   int
+		stdout string // text stdout must contain
+		stderr string // text stderr must contain
+	}{
+		{nil, exitUsage, "", "Usage:"},
+		{[]string{"--help"}, exitOK, "Usage:", ""},
+		{[]string{"-h"}, exitOK, "learngo run <package.Demo>", ""},
+		{[]string{"frobnicate"}, exitUsage, "", `unknown command "frobnicate"`},
+		{[]string{"help"}, exitOK, "Usage:", ""},
+		{[]string{"help", "run"}, exitOK, "Usage: learngo run", ""},
+		{[]string{"help", "clitest.Echo"}, exitOK, "-times", ""},
+		{[]string{"help", "nothing"}, exitUsage, "", `unknown command or demo "nothing"`},
+		{[]string{"help", "run", "list"}, exitUsage, "", "Usage: learngo help"},
+		{[]string{"list", "clitest."}, exitOK, "clitest.Echo\nclitest.Fail\n", ""},
+		{[]string{"list", "-v", "clitest.E"}, exitOK, `-times      int    number of times to repeat the greeting (default "3")`, ""},
+		{[]string{"list", "a", "b"}, exitUsage, "", "Usage: learngo list"},
+		{[]string{"list", "-x"}, exitUsage, "", "flag provided but not defined: -x"},
+		{[]string{"list", "--help"}, exitOK, "", "Usage: learngo list"},
+		{[]string{"run"}, exitUsage, "", "missing demo name"},
+		{[]string{"run", "clitest.Nothing"}, exitUsage, "", `unknown demo "clitest.Nothing"`},
+		{[]string{"run", "clitest.Echo"}, exitOK, "Annica 3 false\n", ""},
+		{[]string{"run", "clitest.Echo", "-name", "Bob", "-times=2", "-isFormal"}, exitOK, "Bob 2 true\n", ""},
+		{[]string{"run", "clitest.Echo", "-isFormal=false"}, exitOK, "Annica 3 false\n", ""},
+		{[]string{"run", "clitest.Echo", "-times", "two"}, exitUsage, "", `times must be int, got "two"`},
+		{[]string{"run", "clitest.Echo", "-isFormal=maybe"}, exitUsage, "", `isFormal must be bool, got "maybe"`},
+		{[]string{"run", "clitest.Echo", "-nickname", "B"}, exitUsage, "", "flag provided but not defined: -nickname"},
+		{[]string{"run", "clitest.Echo", "extra"}, exitUsage, "", `unexpected arguments ["extra"]`},
+		{[]string{"run", "clitest.Echo", "-help"}, exitOK, "", "Usage: learngo run clitest.Echo"},
+		{[]string{"run", "clitest.Fail"}, exitFailure, "", "it went wrong"},
+		{[]string{"serve", "extra"}, exitUsage, "", "Usage: learngo serve"},
+		{[]string{"import"}, exitUsage, "", "Usage: learngo import"},
+		{[]string{"import", "roster.tar.gz"}, exitUsage, "", "unknown"},
+		{[]string{"salutations"}, exitUsage, "", "the only subcommand is query"},
+		{[]string{"salutations", "query", "-format", "toml"}, exitUsage, "", "unknown"},
+		{[]string{"salutations", "query", "name", "=", "Annica"}, exitOK, "Annica", ""},
+		{[]string{"salutations", "query", "nickname = Bob"}, exitUsage, "", "  nickname = Bob\n  ^"},
+		{[]string{"salutations", "query", `name = "Zoë" x`}, exitUsage, "", "  name = \"Zoë\" x\n               ^\n"},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(test.args, &stdout, &stderr)
+		if code != test.code || !strings.Contains(stdout.String(), test.stdout) || !strings.Contains(stderr.String(), test.stderr) {
+			t.Errorf("run(%q) = %d, want %d\nstdout:\n%s\nstderr:\n%s", test.args, code, test.code, &stdout, &stderr)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package clock
+
+import "time"
+
+// Code that calls time.Sleep or time.NewTimer directly can only be tested by really waiting, which is slow,
+// and by guessing how long to wait, which is flaky. Code that asks a Clock instead can be handed:
+//   Real - the time package, for normal use
+//   Fake - a clock that only moves when Advance is called, so a test decides exactly when every timer fires
+// A test using a Fake runs in milliseconds however long the sleeps are, and gives the same result every time.
+
+// Clock is the part of the time package that concerns waiting
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Until(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a time.Timer from a Clock
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is a time.Ticker from a Clock
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// Real is the clock of the time package
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Until(t time.Time) time.Duration        { return time.Until(t) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct{ *time.Timer }
+
+func (timer realTimer) C() <-chan time.Time { return timer.Timer.C }
+
+type realTicker struct{ *time.Ticker }
+
+func (ticker realTicker) C() <-chan time.Time { return ticker.Ticker.C }
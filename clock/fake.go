@@ -0,0 +1,205 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only changes when Advance or Set is called.
+// Timers, tickers, After and Sleep all wait for the fake time to reach them, then fire in order.
+// Sleep blocks its goroutine until another goroutine advances the clock far enough, so a test usually
+// calls BlockUntil first to be sure the code under test has started waiting:
+//
+//	fake := clock.NewFake(start)
+//	go func() { fake.Sleep(time.Hour); close(done) }()
+//	fake.BlockUntil(1)
+//	fake.Advance(time.Hour) // done is closed straight away
+type Fake struct {
+	mutex   sync.Mutex
+	changed *sync.Cond // broadcast whenever a waiter is added or removed
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a Timer, or the workings of a Ticker when period isn't 0
+type fakeWaiter struct {
+	clock   *Fake
+	channel chan time.Time
+	when    time.Time
+	period  time.Duration
+}
+
+// NewFake creates a fake clock reading now
+func NewFake(now time.Time) *Fake {
+	fake := &Fake{now: now}
+	fake.changed = sync.NewCond(&fake.mutex)
+	return fake
+}
+
+// Now returns the fake time
+func (fake *Fake) Now() time.Time {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.now
+}
+
+// Since returns the fake time elapsed since t
+func (fake *Fake) Since(t time.Time) time.Duration {
+	return fake.Now().Sub(t)
+}
+
+// Until returns the fake time left until t
+func (fake *Fake) Until(t time.Time) time.Duration {
+	return t.Sub(fake.Now())
+}
+
+// Sleep blocks until the clock has been advanced by d
+func (fake *Fake) Sleep(d time.Duration) {
+	<-fake.After(d)
+}
+
+// After returns a channel that receives the fake time once the clock has been advanced by d
+func (fake *Fake) After(d time.Duration) <-chan time.Time {
+	return fake.NewTimer(d).C()
+}
+
+// NewTimer creates a timer that fires once the clock has been advanced by d
+func (fake *Fake) NewTimer(d time.Duration) Timer {
+	return fake.add(d, 0)
+}
+
+// NewTicker creates a ticker that fires every time the clock passes another d
+func (fake *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return fakeTicker{fake.add(d, d)}
+}
+
+func (fake *Fake) add(d, period time.Duration) *fakeWaiter {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	// like a time.Timer the channel has room for one value, so firing never blocks the goroutine advancing the clock
+	waiter := &fakeWaiter{clock: fake, channel: make(chan time.Time, 1), when: fake.now.Add(d), period: period}
+	if d <= 0 {
+		waiter.channel <- fake.now
+		return waiter
+	}
+	fake.schedule(waiter)
+	return waiter
+}
+
+// schedule adds waiter to the list in the order it will fire. The lock must be held
+func (fake *Fake) schedule(waiter *fakeWaiter) {
+	i := sort.Search(len(fake.waiters), func(i int) bool { return fake.waiters[i].when.After(waiter.when) })
+	fake.waiters = append(fake.waiters, nil)
+	copy(fake.waiters[i+1:], fake.waiters[i:])
+	fake.waiters[i] = waiter
+	fake.changed.Broadcast()
+}
+
+// unschedule removes waiter from the list, reporting whether it was there. The lock must be held
+func (fake *Fake) unschedule(waiter *fakeWaiter) bool {
+	for i, w := range fake.waiters {
+		if w == waiter {
+			fake.waiters = append(fake.waiters[:i], fake.waiters[i+1:]...)
+			fake.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that comes due on the way, in order
+func (fake *Fake) Advance(d time.Duration) {
+	fake.Set(fake.Now().Add(d))
+}
+
+// Set moves the clock to t, firing every timer and ticker due by then. The clock never goes backwards
+func (fake *Fake) Set(t time.Time) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	for len(fake.waiters) > 0 && !fake.waiters[0].when.After(t) {
+		waiter := fake.waiters[0]
+		fake.waiters = fake.waiters[1:]
+		if waiter.when.After(fake.now) {
+			fake.now = waiter.when
+		}
+		select {
+		case waiter.channel <- fake.now:
+		default:
+			// a ticker nobody is reading drops ticks, just like time.Ticker
+		}
+		if waiter.period > 0 {
+			waiter.when = waiter.when.Add(waiter.period)
+			fake.schedule(waiter)
+		}
+	}
+	if t.After(fake.now) {
+		fake.now = t
+	}
+	fake.changed.Broadcast()
+}
+
+// Waiters returns the number of timers and tickers (including sleeping goroutines) that haven't fired yet
+func (fake *Fake) Waiters() int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return len(fake.waiters)
+}
+
+// BlockUntil waits until at least n timers, tickers or sleepers are waiting on the clock
+func (fake *Fake) BlockUntil(n int) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	for len(fake.waiters) < n {
+		fake.changed.Wait()
+	}
+}
+
+func (waiter *fakeWaiter) C() <-chan time.Time {
+	return waiter.channel
+}
+
+// Stop stops a timer, reporting whether it stopped it before it fired
+func (waiter *fakeWaiter) Stop() bool {
+	waiter.clock.mutex.Lock()
+	defer waiter.clock.mutex.Unlock()
+	return waiter.clock.unschedule(waiter)
+}
+
+// Reset restarts a timer (or changes a ticker's interval) to fire d from now, reporting whether it was still waiting
+func (waiter *fakeWaiter) Reset(d time.Duration) bool {
+	fake := waiter.clock
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	waiting := fake.unschedule(waiter)
+	if waiter.period > 0 {
+		waiter.period = d
+	}
+	waiter.when = fake.now.Add(d)
+	if d <= 0 && waiter.period == 0 {
+		select {
+		case waiter.channel <- fake.now:
+		default:
+		}
+		return waiting
+	}
+	fake.schedule(waiter)
+	return waiting
+}
+
+// fakeTicker gives a fakeWaiter the method set of a Ticker
+type fakeTicker struct{ *fakeWaiter }
+
+func (ticker fakeTicker) Stop() {
+	ticker.fakeWaiter.Stop()
+}
+
+func (ticker fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	ticker.fakeWaiter.Reset(d)
+}
@@ -0,0 +1,122 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+// fired reports whether channel has a value waiting, without blocking
+func fired(channel <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-channel:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeTimersFireInOrder(t *testing.T) {
+	fake := NewFake(start)
+	late, early := fake.NewTimer(2*time.Minute), fake.NewTimer(time.Minute)
+	fake.Advance(59 * time.Second)
+	if _, ok := fired(early.C()); ok {
+		t.Fatal("a one minute timer fired after 59 seconds")
+	}
+	fake.Advance(time.Second)
+	if when, ok := fired(early.C()); !ok || !when.Equal(start.Add(time.Minute)) {
+		t.Fatalf("the one minute timer gave %v, %v", when, ok)
+	}
+	if _, ok := fired(late.C()); ok {
+		t.Fatal("the two minute timer fired after one minute")
+	}
+	// a jump past several timers fires each one at its own time
+	fake.Advance(time.Hour)
+	if when, ok := fired(late.C()); !ok || !when.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("the two minute timer gave %v, %v", when, ok)
+	}
+	if !fake.Now().Equal(start.Add(time.Hour + time.Minute)) {
+		t.Errorf("Now = %v after advancing an hour and a minute", fake.Now())
+	}
+}
+
+func TestFakeTimerStopAndReset(t *testing.T) {
+	fake := NewFake(start)
+	timer := fake.NewTimer(time.Minute)
+	if !timer.Stop() {
+		t.Error("Stop reported the timer had already fired")
+	}
+	fake.Advance(time.Hour)
+	if _, ok := fired(timer.C()); ok {
+		t.Error("a stopped timer fired")
+	}
+	if timer.Reset(time.Second) {
+		t.Error("Reset reported a stopped timer was still waiting")
+	}
+	fake.Advance(time.Second)
+	if _, ok := fired(timer.C()); !ok {
+		t.Error("a reset timer didn't fire")
+	}
+	if _, ok := fired(fake.After(0)); !ok {
+		t.Error("After(0) didn't fire straight away")
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	fake := NewFake(start)
+	ticker := fake.NewTicker(time.Second)
+	defer ticker.Stop()
+	for i := 1; i <= 3; i++ {
+		fake.Advance(time.Second)
+		if when, ok := fired(ticker.C()); !ok || !when.Equal(start.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("tick %d gave %v, %v", i, when, ok)
+		}
+	}
+	// nobody reads during a long jump, so like time.Ticker the extra ticks are dropped
+	fake.Advance(time.Minute)
+	if _, ok := fired(ticker.C()); !ok {
+		t.Fatal("no tick after a minute")
+	}
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("a ticker kept more than one tick")
+	}
+	ticker.Stop()
+	fake.Advance(time.Minute)
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("a stopped ticker ticked")
+	}
+}
+
+func TestFakeSleepWithBlockUntil(t *testing.T) {
+	fake := NewFake(start)
+	woke := make(chan time.Time)
+	go func() {
+		fake.Sleep(time.Hour)
+		woke <- fake.Now()
+	}()
+	fake.BlockUntil(1)
+	fake.Advance(time.Hour)
+	select {
+	case now := <-woke:
+		if !now.Equal(start.Add(time.Hour)) {
+			t.Errorf("woke at %v", now)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Sleep didn't return after the clock was advanced")
+	}
+	if fake.Waiters() != 0 {
+		t.Errorf("%d waiters left", fake.Waiters())
+	}
+}
+
+func TestFakeNeverGoesBackwards(t *testing.T) {
+	fake := NewFake(start)
+	fake.Set(start.Add(-time.Hour))
+	if !fake.Now().Equal(start) {
+		t.Errorf("Now = %v after setting an earlier time", fake.Now())
+	}
+	if fake.Since(start) != 0 || fake.Until(start.Add(time.Minute)) != time.Minute {
+		t.Error("Since or Until don't use the fake time")
+	}
+}
@@ -0,0 +1,271 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// A Codec reads and writes core.Salutations in one file format:
+//   JSON      - an array of objects, or one object per line (NDJSON)
+//   CSV       - a header row naming the columns, then one salutation per row
+//   XML       - <salutations><salutation name="Annica">...</salutation></salutations>
+//   KeyValue  - "name: Annica" lines, with a blank line between salutations
+// Every format uses the same field names - the JSON names in core.Salutation's struct tags.
+// Encoders and decoders work one salutation at a time, so a file of any size can be streamed
+// without holding all of it in memory. Marshal and Unmarshal are the convenient forms for a value in memory.
+// greeting.Salutation and goInterfaces.Salutation convert to and from core.Salutation with the functions in core.
+
+// Encoder writes salutations one at a time. Close writes whatever the format needs after the last one
+// (such as a closing bracket) and flushes - it doesn't close the underlying io.Writer
+type Encoder interface {
+	Encode(salutation core.Salutation) error
+	Close() error
+}
+
+// Decoder reads salutations one at a time, returning io.EOF after the last one.
+// A *DecodeError for a single bad record can be skipped - the next call to Decode carries on with the next record.
+// Any other error (a syntax error that loses track of where records start, or a read error) is returned again by every later call
+type Decoder interface {
+	Decode() (core.Salutation, error)
+}
+
+// Positioner is implemented by every Decoder in this package. Position returns the number of the record
+// Decode read last and the line it starts on, both counting from 1 - the line is 0 when the format can't tell
+type Positioner interface {
+	Position() (record, line int)
+}
+
+// Resumer is implemented by every Decoder in this package. Mark returns the place in the input after the last
+// record Decode read, so that Resume can carry on from there later without decoding every record before it again
+type Resumer interface {
+	Mark() Mark
+}
+
+// Mark is a place between two records in a Decoder's input
+type Mark struct {
+	Offset  int64  // bytes of input before it
+	Records int    // records before it
+	Lines   int    // lines before it
+	State   string // anything else the format needs to carry on, such as the CSV header
+}
+
+// Codec is a file format for salutations
+type Codec interface {
+	Name() string
+	Extensions() []string // such as ".csv", used by ForPath
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Errors returned by the codecs. Use errors.Is to check for them
+var (
+	ErrUnknownFormat = errors.New("codec: unknown format")
+	ErrUnknownField  = errors.New("codec: unknown field")
+	ErrNoRecords     = errors.New("codec: no salutation found")
+	ErrManyRecords   = errors.New("codec: more than one salutation found")
+	ErrBadMark       = errors.New("codec: can't resume from this mark")
+)
+
+// DecodeError reports a problem with a single record
+type DecodeError struct {
+	Format string
+	Record int // counting from 1 - 0 for a problem outside any record, such as in a CSV header
+	Line   int // counting from 1 - 0 when the format can't tell
+	Err    error
+}
+
+func (err *DecodeError) Error() string {
+	switch {
+	case err.Record > 0 && err.Line > 0:
+		return fmt.Sprintf("codec: %s record %d (line %d): %v", err.Format, err.Record, err.Line, err.Err)
+	case err.Record > 0:
+		return fmt.Sprintf("codec: %s record %d: %v", err.Format, err.Record, err.Err)
+	case err.Line > 0:
+		return fmt.Sprintf("codec: %s line %d: %v", err.Format, err.Line, err.Err)
+	default:
+		return fmt.Sprintf("codec: %s: %v", err.Format, err.Err)
+	}
+}
+
+func (err *DecodeError) Unwrap() error {
+	return err.Err
+}
+
+// field is a core.Salutation field as CSV columns and key: value lines name it
+type field struct {
+	name string
+	get  func(s *core.Salutation) *string
+}
+
+// fields in the order they are written
+var fields = []field{
+	{"name", func(s *core.Salutation) *string { return &s.Name }},
+	{"casualGreeting", func(s *core.Salutation) *string { return &s.CasualGreeting }},
+	{"formalGreeting", func(s *core.Salutation) *string { return &s.FormalGreeting }},
+	{"prefix", func(s *core.Salutation) *string { return &s.Prefix }},
+	{"locale", func(s *core.Salutation) *string { return &s.Locale }},
+}
+
+// fieldAliases are the other names accepted for each field, written in lower case without spaces, dashes or underscores
+var fieldAliases = map[string]string{
+	"name":           "name",
+	"casualgreeting": "casualGreeting",
+	"casual":         "casualGreeting",
+	"greeting":       "casualGreeting",
+	"formalgreeting": "formalGreeting",
+	"formal":         "formalGreeting",
+	"prefix":         "prefix",
+	"title":          "prefix",
+	"honorific":      "prefix",
+	"locale":         "locale",
+	"language":       "locale",
+	"lang":           "locale",
+}
+
+// findField looks a field up by any of its names, ignoring case, spaces, dashes and underscores
+func findField(name string, aliases map[string]string) (field, bool) {
+	key := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+	canonical, exists := aliases[key]
+	if !exists {
+		canonical, exists = fieldAliases[key]
+	}
+	for _, f := range fields {
+		if exists && f.name == canonical {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// codecs by name
+var codecs = map[string]Codec{}
+
+func register(codec Codec) {
+	codecs[codec.Name()] = codec
+}
+
+// Names returns the name of every codec in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup finds a codec by name, ignoring case - "json", "csv", "xml" or "keyvalue"
+func Lookup(name string) (Codec, error) {
+	if codec, exists := codecs[strings.ToLower(name)]; exists {
+		return codec, nil
+	}
+	return nil, fmt.Errorf("%w %q - use one of %s", ErrUnknownFormat, name, strings.Join(Names(), ", "))
+}
+
+// ForPath finds the codec for a file from its extension
+func ForPath(path string) (Codec, error) {
+	extension := strings.ToLower(filepath.Ext(path))
+	for _, name := range Names() {
+		for _, e := range codecs[name].Extensions() {
+			if e == extension {
+				return codecs[name], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: no format uses the extension %q of %s", ErrUnknownFormat, extension, path)
+}
+
+// WriteAll encodes every salutation to w
+func WriteAll(codec Codec, w io.Writer, salutations core.Salutations) error {
+	encoder := codec.NewEncoder(w)
+	for _, salutation := range salutations {
+		if err := encoder.Encode(salutation); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+// ReadAll decodes every salutation from r, stopping at the first error
+func ReadAll(codec Codec, r io.Reader) (salutations core.Salutations, err error) {
+	decoder := codec.NewDecoder(r)
+	for {
+		salutation, err := decoder.Decode()
+		if err == io.EOF {
+			return salutations, nil
+		}
+		if err != nil {
+			return salutations, err
+		}
+		salutations = append(salutations, salutation)
+	}
+}
+
+// MarshalAll encodes the salutations
+func MarshalAll(codec Codec, salutations core.Salutations) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := WriteAll(codec, &buffer, salutations)
+	return buffer.Bytes(), err
+}
+
+// UnmarshalAll decodes every salutation in data
+func UnmarshalAll(codec Codec, data []byte) (core.Salutations, error) {
+	return ReadAll(codec, bytes.NewReader(data))
+}
+
+// Marshal encodes a single salutation
+func Marshal(codec Codec, salutation core.Salutation) ([]byte, error) {
+	return MarshalAll(codec, core.Salutations{salutation})
+}
+
+// Unmarshal decodes data holding exactly one salutation
+func Unmarshal(codec Codec, data []byte) (core.Salutation, error) {
+	salutations, err := UnmarshalAll(codec, data)
+	switch {
+	case err != nil:
+		return core.Salutation{}, err
+	case len(salutations) == 0:
+		return core.Salutation{}, ErrNoRecords
+	case len(salutations) > 1:
+		return core.Salutation{}, fmt.Errorf("%w: %d", ErrManyRecords, len(salutations))
+	}
+	return salutations[0], nil
+}
+
+// resumer is implemented by the codecs in this package. r has already been moved to the mark's offset
+type resumer interface {
+	resume(r io.Reader, mark Mark) (Decoder, error)
+}
+
+// Resume returns a decoder for codec that carries on at mark in r, which must hold the input the mark was made for.
+// Records and lines are counted on from the mark, so Position and DecodeError still give the place in the whole input.
+// It returns ErrBadMark for a Codec from outside this package, or a mark this codec didn't make
+func Resume(codec Codec, r io.ReadSeeker, mark Mark) (Decoder, error) {
+	resumable, ok := codec.(resumer)
+	if !ok || mark.Offset < 0 || mark.Records < 0 || mark.Lines < 0 {
+		return nil, fmt.Errorf("%w for %s", ErrBadMark, codec.Name())
+	}
+	if _, err := r.Seek(mark.Offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("codec: %w", err)
+	}
+	return resumable.resume(r, mark)
+}
+
+// stickyError remembers the first fatal error so a decoder can keep returning it
+type stickyError struct {
+	err error
+}
+
+func (sticky *stickyError) fail(err error) error {
+	if sticky.err == nil {
+		sticky.err = err
+	}
+	return sticky.err
+}
@@ -0,0 +1,199 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// awkward holds salutations whose values trip up a careless encoder in at least one format
+var awkward = map[string]core.Salutations{
+	"empty": nil,
+	"plain": {
+		{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello", Prefix: "Ms ", Locale: "en"},
+		{Name: "Mitchel"},
+	},
+	"commas":   {{Name: "Burns, Annica", CasualGreeting: "Hi, there", FormalGreeting: ",", Prefix: "Dr,"}},
+	"quotes":   {{Name: `Annica "Nic" Burns`, CasualGreeting: `'ello`, FormalGreeting: `"`, Prefix: `\"`}},
+	"newlines": {{Name: "Annica\nBurns", CasualGreeting: "Hi\r\nthere", FormalGreeting: "\n", Prefix: "Ms\n"}},
+	"equals and comments": {
+		{Name: "a=b", CasualGreeting: "key: value", FormalGreeting: "Hello # not a comment", Prefix: "- "},
+		{Name: "#hash", CasualGreeting: "=", FormalGreeting: ": ", Prefix: "[x]"},
+	},
+	"markup":     {{Name: "<Annica>", CasualGreeting: "Hi & bye", FormalGreeting: "<![CDATA[x]]>", Prefix: "&amp;"}},
+	"non-ASCII":  {{Name: "Zoë Ñúñez", CasualGreeting: "¡Hola!", FormalGreeting: "こんにちは", Prefix: "Herr ", Locale: "es-MX"}},
+	"spaces":     {{Name: "  Annica  ", CasualGreeting: "\tHowdy", FormalGreeting: " ", Prefix: "Ms "}},
+	"only names": {{Name: "Annica"}, {Name: "Mitchel"}, {Name: "Marisol"}},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, name := range Names() {
+		codec, err := Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for label, salutations := range awkward {
+			data, err := MarshalAll(codec, salutations)
+			if err != nil {
+				t.Errorf("%s, %s: MarshalAll = %v", name, label, err)
+				continue
+			}
+			decoded, err := UnmarshalAll(codec, data)
+			if err != nil {
+				t.Errorf("%s, %s: UnmarshalAll = %v\n%s", name, label, err, data)
+				continue
+			}
+			want := salutations
+			if name == "csv" && label == "newlines" {
+				// encoding/csv reads "\r\n" inside a quoted value as "\n"
+				want = slices.Clone(salutations)
+				want[0].CasualGreeting = "Hi\nthere"
+			}
+			if !slices.Equal(decoded, want) {
+				t.Errorf("%s, %s: round trip gave\n%q\nwant\n%q\nencoded as\n%s", name, label, decoded, want, data)
+			}
+		}
+	}
+}
+
+func TestMarshalAndUnmarshalOne(t *testing.T) {
+	salutation := core.Salutation{Name: "Zoë", CasualGreeting: "Hi, \"you\"", Prefix: "Dr "}
+	for _, name := range Names() {
+		codec, _ := Lookup(name)
+		data, err := Marshal(codec, salutation)
+		if err != nil {
+			t.Fatalf("%s: Marshal = %v", name, err)
+		}
+		if decoded, err := Unmarshal(codec, data); err != nil || decoded != salutation {
+			t.Errorf("%s: Unmarshal = %+v, %v, want %+v", name, decoded, err, salutation)
+		}
+		two, _ := MarshalAll(codec, core.Salutations{salutation, salutation})
+		if _, err := Unmarshal(codec, two); !errors.Is(err, ErrManyRecords) {
+			t.Errorf("%s: Unmarshal of two salutations = %v, want ErrManyRecords", name, err)
+		}
+		empty, _ := MarshalAll(codec, nil)
+		if _, err := Unmarshal(codec, empty); !errors.Is(err, ErrNoRecords) {
+			t.Errorf("%s: Unmarshal of no salutations = %v, want ErrNoRecords", name, err)
+		}
+	}
+}
+
+func TestDecodersReadEmptyInput(t *testing.T) {
+	for _, name := range Names() {
+		codec, _ := Lookup(name)
+		if salutation, err := codec.NewDecoder(strings.NewReader("")).Decode(); err != io.EOF {
+			t.Errorf("%s: decoding nothing = %+v, %v, want io.EOF", name, salutation, err)
+		}
+	}
+}
+
+func TestForPath(t *testing.T) {
+	tests := map[string]string{
+		"roster.json":        "json",
+		"roster.JSONL":       "ndjson",
+		"dir.d/roster.csv":   "csv",
+		"roster.xml":         "xml",
+		"roster.yaml":        "keyvalue",
+		"roster.tar.gz":      "",
+		"roster-without-ext": "",
+	}
+	for path, want := range tests {
+		codec, err := ForPath(path)
+		switch {
+		case want == "" && !errors.Is(err, ErrUnknownFormat):
+			t.Errorf("ForPath(%q) = %v, want ErrUnknownFormat", path, err)
+		case want != "" && (err != nil || codec.Name() != want):
+			t.Errorf("ForPath(%q) = %v, %v, want %s", path, codec, err, want)
+		}
+	}
+}
+
+// resumable holds input for each codec with comments, blank lines, enclosing elements and a bad record,
+// so a resumed decoder has more than the records themselves to find its place in
+var resumable = map[string]string{
+	"json":   "[\n  {\"name\": \"Annica\"},\n\n  {\"name\": \"Bob\", \"nickname\": \"B\"},\n  {\"name\": \"Mitchel\",\n   \"prefix\": \"Mr\"}\n]\n",
+	"ndjson": "{\"name\": \"Annica\"}\n\n{\"name\": \"Bob\", \"nickname\": \"B\"}\n{\"name\": \"Mitchel\"}\n",
+	"csv":    "name,prefix\nAnnica,Ms\n\"Bob\nBurns\",Dr,extra\n\"Mitchel\",\"Mr\n\"\nMarisol,\n",
+	"xml": "<?xml version=\"1.0\"?>\n<roster>\n<salutations>\n  <salutation name=\"Annica\"><prefix>Ms</prefix></salutation>\n" +
+		"  <!-- a comment -->\n  <salutation\n    name=\"Bob\">\n    <nickname>B</nickname>\n  </salutation>\n</salutations>\n" +
+		"<salutations><salutation name=\"Mitchel\"/></salutations>\n</roster>\n",
+	"keyvalue": "# the roster\n- name: Annica\n  prefix: Ms\n- name: Bob\n  nickname: B\n\n\n- name: Mitchel\n---\nname: Marisol\n",
+}
+
+// decoded is what one call to Decode gave
+type decoded struct {
+	salutation   core.Salutation
+	err          string
+	record, line int
+}
+
+// decodeRest decodes what is left in decoder, or at most max records if max isn't negative
+func decodeRest(decoder Decoder, max int) (all []decoded) {
+	for ; max != 0; max-- {
+		salutation, err := decoder.Decode()
+		if err == io.EOF {
+			return all
+		}
+		record, line := decoder.(Positioner).Position()
+		all = append(all, decoded{salutation, fmt.Sprint(err), record, line})
+		if err != nil && !errors.As(err, new(*DecodeError)) {
+			return all
+		}
+	}
+	return all
+}
+
+func TestResumeCarriesOnWhereTheMarkWasMade(t *testing.T) {
+	for _, name := range Names() {
+		codec, _ := Lookup(name)
+		inputs := []string{resumable[name]}
+		for _, salutations := range awkward {
+			data, _ := MarshalAll(codec, salutations)
+			inputs = append(inputs, string(data))
+		}
+		for _, input := range inputs {
+			want := decodeRest(codec.NewDecoder(strings.NewReader(input)), -1)
+			for k := 0; k <= len(want); k++ {
+				decoder := codec.NewDecoder(strings.NewReader(input))
+				got := decodeRest(decoder, k)
+				mark := decoder.(Resumer).Mark()
+				resumed, err := Resume(codec, bytes.NewReader([]byte(input)), mark)
+				if err != nil {
+					t.Errorf("%s: Resume(%+v) = %v\n%s", name, mark, err, input)
+					continue
+				}
+				got = append(got, decodeRest(resumed, -1)...)
+				if !slices.Equal(got, want) {
+					t.Errorf("%s: resuming after %d records at %+v gave\n%v\nwant\n%v\nfrom\n%s", name, k, mark, got, want, input)
+				}
+			}
+		}
+	}
+}
+
+func TestResumeRejectsBadMarks(t *testing.T) {
+	tests := []struct {
+		codec Codec
+		mark  Mark
+	}{
+		{JSON, Mark{Offset: -1}},
+		{JSON, Mark{Offset: 0, State: "["}},
+		{JSON, Mark{Offset: 5, State: "{"}},
+		{CSV, Mark{Offset: 5}},
+		{CSV, Mark{Offset: 5, State: "name,nickname"}},
+		{XML, Mark{Offset: 5, State: "a><b"}},
+		{XML, Mark{Offset: 5, State: "x:a"}},
+		{KeyValue, Mark{Records: -1}},
+	}
+	for _, test := range tests {
+		if _, err := Resume(test.codec, strings.NewReader("[]"), test.mark); !errors.Is(err, ErrBadMark) {
+			t.Errorf("%s: Resume(%+v) = %v, want ErrBadMark", test.codec.Name(), test.mark, err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package codec
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// CSV starts with a header row naming the columns, then has one salutation per row:
+//
+//	name,casualGreeting,formalGreeting,prefix,locale
+//	Annica,Howdy,Hello,Ms ,en
+//
+// The decoder maps the header to fields by name, ignoring case, spaces, dashes and underscores, so the columns
+// can come in any order and "Casual Greeting" finds casualGreeting. A few common alternatives are understood
+// too - "greeting" for casualGreeting, "title" for prefix, "language" for locale - and CSVCodec.Aliases adds more.
+// Only the name column is required. A header naming a column no field matches is an ErrUnknownField.
+// Values may hold commas, quotes and newlines, but like encoding/csv the decoder reads "\r\n" inside a value as "\n"
+var CSV Codec = CSVCodec{}
+
+func init() {
+	register(CSV)
+}
+
+// CSVCodec is the CSV format with extra header names or a different separator
+type CSVCodec struct {
+	// Aliases maps extra header names to the field they mean, such as "vorname" to "name".
+	// Write the extra names in lower case without spaces, dashes or underscores
+	Aliases map[string]string
+	Comma   rune // the separator - ',' if it isn't set
+}
+
+func (codec CSVCodec) Name() string         { return "csv" }
+func (codec CSVCodec) Extensions() []string { return []string{".csv"} }
+
+func (codec CSVCodec) NewEncoder(w io.Writer) Encoder {
+	writer := csv.NewWriter(w)
+	if codec.Comma != 0 {
+		writer.Comma = codec.Comma
+	}
+	return &csvEncoder{csv: writer}
+}
+
+func (codec CSVCodec) NewDecoder(r io.Reader) Decoder {
+	reader := csv.NewReader(r)
+	if codec.Comma != 0 {
+		reader.Comma = codec.Comma
+	}
+	// rows with the wrong number of columns are reported by Decode rather than by csv.Reader
+	reader.FieldsPerRecord = -1
+	return &csvDecoder{codec: codec, csv: reader}
+}
+
+type csvEncoder struct {
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+func (encoder *csvEncoder) writeHeader() {
+	if encoder.headerWritten {
+		return
+	}
+	encoder.headerWritten = true
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+	encoder.csv.Write(header)
+}
+
+func (encoder *csvEncoder) Encode(salutation core.Salutation) error {
+	encoder.writeHeader()
+	row := make([]string, len(fields))
+	for i, f := range fields {
+		row[i] = *f.get(&salutation)
+	}
+	if err := encoder.csv.Write(row); err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	return nil
+}
+
+func (encoder *csvEncoder) Close() error {
+	encoder.writeHeader()
+	encoder.csv.Flush()
+	if err := encoder.csv.Error(); err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	return nil
+}
+
+type csvDecoder struct {
+	codec    CSVCodec
+	csv      *csv.Reader
+	columns  []field // the field for each column, from the header
+	base     int64   // the offset in the whole input of csv.Reader's offset 0
+	lineBase int     // the lines in the whole input before csv.Reader's line 1
+	records  int
+	line     int
+	end      int // the line the row Decode read last ends on, counted by csv.Reader
+	stickyError
+}
+
+func (decoder *csvDecoder) Position() (record, line int) {
+	return decoder.records, decoder.line
+}
+
+// Mark holds the header as the names of the fields, so a resumed decoder doesn't have to read it again
+func (decoder *csvDecoder) Mark() Mark {
+	names := make([]string, len(decoder.columns))
+	for i, f := range decoder.columns {
+		names[i] = f.name
+	}
+	return Mark{Offset: decoder.base + decoder.csv.InputOffset(), Records: decoder.records, Lines: decoder.lineBase + decoder.end, State: strings.Join(names, ",")}
+}
+
+func (codec CSVCodec) resume(r io.Reader, mark Mark) (Decoder, error) {
+	decoder := codec.NewDecoder(r).(*csvDecoder)
+	decoder.base, decoder.lineBase, decoder.records = mark.Offset, mark.Lines, mark.Records
+	if mark.State == "" {
+		if mark.Offset != 0 {
+			return nil, fmt.Errorf("%w for csv", ErrBadMark)
+		}
+		return decoder, nil
+	}
+	for _, name := range strings.Split(mark.State, ",") {
+		f, exists := findField(name, nil)
+		if !exists {
+			return nil, fmt.Errorf("%w for csv", ErrBadMark)
+		}
+		decoder.columns = append(decoder.columns, f)
+	}
+	return decoder, nil
+}
+
+// rowEnd returns the line a row read by csv.Reader ends on - the line its last field starts on,
+// plus any line breaks inside that field
+func (decoder *csvDecoder) rowEnd(row []string) int {
+	if len(row) == 0 {
+		return decoder.end
+	}
+	line, _ := decoder.csv.FieldPos(len(row) - 1)
+	return line + strings.Count(row[len(row)-1], "\n")
+}
+
+func (decoder *csvDecoder) Decode() (salutation core.Salutation, err error) {
+	if decoder.err != nil {
+		return salutation, decoder.err
+	}
+	if decoder.columns == nil {
+		if err := decoder.readHeader(); err != nil {
+			return salutation, decoder.fail(err)
+		}
+	}
+	row, err := decoder.csv.Read()
+	if err == io.EOF {
+		return salutation, decoder.fail(io.EOF)
+	}
+	decoder.records++
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		// csv.Reader carries on from the next line after a badly quoted field
+		decoder.line, decoder.end = decoder.lineBase+parseError.StartLine, parseError.Line
+		return salutation, &DecodeError{Format: "csv", Record: decoder.records, Line: decoder.line, Err: parseError.Err}
+	}
+	if err != nil {
+		return salutation, decoder.fail(fmt.Errorf("codec: %w", err))
+	}
+	decoder.line, _ = decoder.csv.FieldPos(0)
+	decoder.line += decoder.lineBase
+	decoder.end = decoder.rowEnd(row)
+	if len(row) != len(decoder.columns) {
+		return salutation, &DecodeError{Format: "csv", Record: decoder.records, Line: decoder.line,
+			Err: fmt.Errorf("%d columns, but the header has %d", len(row), len(decoder.columns))}
+	}
+	for i, value := range row {
+		*decoder.columns[i].get(&salutation) = value
+	}
+	return salutation, nil
+}
+
+func (decoder *csvDecoder) readHeader() error {
+	header, err := decoder.csv.Read()
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return &DecodeError{Format: "csv", Line: 1, Err: fmt.Errorf("reading the header: %w", err)}
+	}
+	line, _ := decoder.csv.FieldPos(0)
+	decoder.end = decoder.rowEnd(header)
+	seen := make(map[string]bool)
+	for i, name := range header {
+		if i == 0 {
+			// a byte order mark from a spreadsheet would stop the first column matching
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		f, exists := findField(name, decoder.codec.Aliases)
+		if !exists {
+			return &DecodeError{Format: "csv", Line: line, Err: fmt.Errorf("%w %q in the header", ErrUnknownField, name)}
+		}
+		if seen[f.name] {
+			return &DecodeError{Format: "csv", Line: line, Err: fmt.Errorf("the header has more than one %s column", f.name)}
+		}
+		seen[f.name] = true
+		decoder.columns = append(decoder.columns, f)
+	}
+	if !seen["name"] {
+		return &DecodeError{Format: "csv", Line: line, Err: errors.New("the header has no name column")}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package codec
+
+import (
+	"io"
+
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/registry"
+	"github.com/annicaburns/learngo/store"
+)
+
+// Register the codec demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "codec.Encode",
+		Description: "write a roster as JSON, NDJSON, CSV, XML or key: value lines",
+		Params: []registry.Param{
+			{Name: "format", Kind: registry.String, Default: "json", Description: "json, ndjson, csv, xml or keyvalue"},
+			registry.RosterParam,
+		},
+		Run: func(w io.Writer, args registry.Args) error {
+			codec, err := Lookup(args.String("format"))
+			if err != nil {
+				return err
+			}
+			roster, err := store.LoadRoster(args.String("roster"), core.VendSalutations())
+			if err != nil {
+				return err
+			}
+			return WriteAll(codec, w, roster)
+		},
+	})
+}
@@ -0,0 +1,247 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// JSON writes an array of salutations, one object to a line:
+//
+//	[
+//		{"name":"Annica","casualGreeting":"Howdy","formalGreeting":"Hello"},
+//		{"name":"Mitchel","casualGreeting":"Hey"}
+//	]
+//
+// NDJSON writes the same objects without the array - one per line, nothing else.
+// Both decoders read either layout, and objects may be spread over several lines.
+// Unknown keys are reported as ErrUnknownField
+var (
+	JSON   Codec = jsonCodec{name: "json", extensions: []string{".json"}}
+	NDJSON Codec = jsonCodec{name: "ndjson", extensions: []string{".ndjson", ".jsonl"}, lines: true}
+)
+
+func init() {
+	register(JSON)
+	register(NDJSON)
+}
+
+type jsonCodec struct {
+	name       string
+	extensions []string
+	lines      bool // NDJSON rather than an array
+}
+
+func (codec jsonCodec) Name() string         { return codec.name }
+func (codec jsonCodec) Extensions() []string { return codec.extensions }
+
+func (codec jsonCodec) NewEncoder(w io.Writer) Encoder {
+	return &jsonEncoder{w: bufio.NewWriter(w), lines: codec.lines}
+}
+
+func (codec jsonCodec) NewDecoder(r io.Reader) Decoder {
+	counter := &lineCounter{r: r}
+	buffered := bufio.NewReader(counter)
+	decoder := &jsonDecoder{format: codec.name, counter: counter, json: json.NewDecoder(buffered)}
+	decoder.json.DisallowUnknownFields()
+	// peek at the first thing in the input to see whether it is an array or a stream of objects
+	for n := 1; ; n++ {
+		peeked, err := buffered.Peek(n)
+		if err != nil {
+			break
+		}
+		if c := peeked[n-1]; !strings.ContainsRune(" \t\r\n", rune(c)) {
+			decoder.array = c == '['
+			break
+		}
+	}
+	return decoder
+}
+
+type jsonEncoder struct {
+	w     *bufio.Writer
+	lines bool
+	count int
+}
+
+func (encoder *jsonEncoder) Encode(salutation core.Salutation) error {
+	data, err := json.Marshal(salutation)
+	if err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	switch {
+	case encoder.lines:
+	case encoder.count == 0:
+		encoder.w.WriteString("[\n\t")
+	default:
+		encoder.w.WriteString(",\n\t")
+	}
+	encoder.w.Write(data)
+	if encoder.lines {
+		encoder.w.WriteByte('\n')
+	}
+	encoder.count++
+	return nil
+}
+
+func (encoder *jsonEncoder) Close() error {
+	switch {
+	case encoder.lines:
+	case encoder.count == 0:
+		encoder.w.WriteString("[]\n")
+	default:
+		encoder.w.WriteString("\n]\n")
+	}
+	if err := encoder.w.Flush(); err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	return nil
+}
+
+type jsonDecoder struct {
+	format  string
+	counter *lineCounter
+	json    *json.Decoder
+	array   bool
+	started bool
+	base    int64 // the offset in the whole input of the json.Decoder's offset 0
+	records int
+	line    int
+	stickyError
+}
+
+func (decoder *jsonDecoder) Position() (record, line int) {
+	return decoder.records, decoder.line
+}
+
+func (decoder *jsonDecoder) Mark() Mark {
+	offset := decoder.json.InputOffset()
+	decoder.counter.advance(offset)
+	mark := Mark{Offset: decoder.base + offset, Records: decoder.records, Lines: decoder.counter.line}
+	if decoder.array && decoder.started {
+		mark.State = "["
+	}
+	return mark
+}
+
+// resumeArrayPrefix stands in for the array up to a mark - the comma or ] that comes next is valid after it
+const resumeArrayPrefix = "[null"
+
+func (codec jsonCodec) resume(r io.Reader, mark Mark) (Decoder, error) {
+	if mark.State == "" {
+		decoder := codec.NewDecoder(r).(*jsonDecoder)
+		decoder.base, decoder.records, decoder.counter.line = mark.Offset, mark.Records, mark.Lines
+		return decoder, nil
+	}
+	if mark.State != "[" || mark.Offset == 0 {
+		return nil, fmt.Errorf("%w for %s", ErrBadMark, codec.name)
+	}
+	decoder := codec.NewDecoder(io.MultiReader(strings.NewReader(resumeArrayPrefix), r)).(*jsonDecoder)
+	decoder.base, decoder.records, decoder.counter.line = mark.Offset-int64(len(resumeArrayPrefix)), mark.Records, mark.Lines
+	// read the [ and the null, which leaves the json.Decoder inside the array where the mark is
+	for i := 0; i < 2; i++ {
+		if _, err := decoder.json.Token(); err != nil {
+			return nil, fmt.Errorf("codec: %w", err)
+		}
+	}
+	decoder.started = true
+	return decoder, nil
+}
+
+func (decoder *jsonDecoder) Decode() (salutation core.Salutation, err error) {
+	if decoder.err != nil {
+		return salutation, decoder.err
+	}
+	if decoder.array && !decoder.started {
+		decoder.started = true
+		if _, err := decoder.json.Token(); err != nil {
+			return salutation, decoder.fail(decoder.syntaxError(err))
+		}
+	}
+	if decoder.array && !decoder.json.More() {
+		// the closing bracket - after it there must be nothing but white space
+		if _, err := decoder.json.Token(); err != nil {
+			return salutation, decoder.fail(decoder.syntaxError(err))
+		}
+		if _, err := decoder.json.Token(); err != io.EOF {
+			return salutation, decoder.fail(decoder.syntaxError(errors.New("unexpected data after the closing ]")))
+		}
+		return salutation, decoder.fail(io.EOF)
+	}
+
+	start := decoder.json.InputOffset()
+	err = decoder.json.Decode(&salutation)
+	if err == io.EOF && !decoder.array {
+		return salutation, decoder.fail(io.EOF)
+	}
+	decoder.records++
+	decoder.line = decoder.counter.recordLine(start)
+	if err == nil {
+		// let go of the input that has been decoded
+		decoder.counter.advance(decoder.json.InputOffset())
+		return salutation, nil
+	}
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) || errors.Is(err, io.ErrUnexpectedEOF) || err == io.EOF {
+		return salutation, decoder.fail(decoder.syntaxError(err))
+	}
+	// the whole object was read, so the next record can still be decoded
+	if strings.HasPrefix(err.Error(), "json: unknown field ") {
+		err = fmt.Errorf("%w %s", ErrUnknownField, strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+	return core.Salutation{}, &DecodeError{Format: decoder.format, Record: decoder.records, Line: decoder.line, Err: err}
+}
+
+func (decoder *jsonDecoder) syntaxError(err error) error {
+	return &DecodeError{Format: decoder.format, Record: decoder.records, Line: decoder.counter.recordLine(decoder.json.InputOffset()), Err: err}
+}
+
+// lineCounter keeps the input a json.Decoder has read but not yet passed, so an offset can be turned into a line number
+type lineCounter struct {
+	r       io.Reader
+	pending []byte // the input from offset on
+	offset  int64
+	line    int // the number of newlines before offset
+}
+
+func (counter *lineCounter) Read(p []byte) (int, error) {
+	n, err := counter.r.Read(p)
+	counter.pending = append(counter.pending, p[:n]...)
+	return n, err
+}
+
+// advance moves to the offset to, counting the lines passed. Offsets must never go backwards
+func (counter *lineCounter) advance(to int64) {
+	n := int(to - counter.offset)
+	if n > len(counter.pending) {
+		n = len(counter.pending)
+	}
+	if n <= 0 {
+		return
+	}
+	counter.line += bytes.Count(counter.pending[:n], []byte{'\n'})
+	counter.pending = counter.pending[n:]
+	counter.offset += int64(n)
+}
+
+// recordLine returns the line of the first byte from the offset from on that isn't white space or a comma
+func (counter *lineCounter) recordLine(from int64) int {
+	counter.advance(from)
+	line := counter.line + 1
+	for _, c := range counter.pending {
+		switch c {
+		case '\n':
+			line++
+		case ' ', '\t', '\r', ',':
+		default:
+			return line
+		}
+	}
+	return line
+}
@@ -0,0 +1,273 @@
+package codec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// KeyValue is a "key: value" format - a small corner of YAML that is easy to write by hand:
+//
+//	# the first salutation
+//	- name: Annica
+//	  casualGreeting: Howdy
+//	  prefix: "Ms "
+//
+//	- name: Mitchel
+//
+// A salutation ends at a blank line or where a line starting with "- " begins the next one, so the "- " is optional.
+// A value may be in double quotes (with Go/YAML escapes) or single quotes, which keeps spaces at either end;
+// otherwise it is trimmed and anything after " #" is a comment. Keys are matched like CSV headers.
+// The encoder leaves out empty fields and writes a list that YAML tools can read
+var KeyValue Codec = keyValueCodec{}
+
+func init() {
+	register(KeyValue)
+}
+
+type keyValueCodec struct{}
+
+func (keyValueCodec) Name() string         { return "keyvalue" }
+func (keyValueCodec) Extensions() []string { return []string{".yaml", ".yml", ".txt"} }
+
+func (keyValueCodec) NewEncoder(w io.Writer) Encoder {
+	return &keyValueEncoder{w: bufio.NewWriter(w)}
+}
+
+func (keyValueCodec) NewDecoder(r io.Reader) Decoder {
+	decoder := &keyValueDecoder{lines: bufio.NewScanner(r)}
+	decoder.lines.Split(decoder.scanLine)
+	return decoder
+}
+
+func (codec keyValueCodec) resume(r io.Reader, mark Mark) (Decoder, error) {
+	decoder := codec.NewDecoder(r).(*keyValueDecoder)
+	decoder.offset, decoder.line, decoder.records = mark.Offset, mark.Lines, mark.Records
+	return decoder, nil
+}
+
+type keyValueEncoder struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (encoder *keyValueEncoder) Encode(salutation core.Salutation) error {
+	if encoder.count > 0 {
+		encoder.w.WriteByte('\n')
+	}
+	encoder.count++
+	marker := "- "
+	for _, f := range fields {
+		value := *f.get(&salutation)
+		if value == "" && f.name != "name" {
+			continue
+		}
+		fmt.Fprintf(encoder.w, "%s%s: %s\n", marker, f.name, quoteValue(value))
+		marker = "  "
+	}
+	return nil
+}
+
+func (encoder *keyValueEncoder) Close() error {
+	if err := encoder.w.Flush(); err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	return nil
+}
+
+// quoteValue puts a value in double quotes if it wouldn't read back the same without them
+func quoteValue(value string) string {
+	if value == "" || value != strings.TrimSpace(value) || strings.ContainsAny(value[:1], `"'#-[{&*!|>%@`+"`") ||
+		strings.Contains(value, " #") || strings.Contains(value, ": ") {
+		return strconv.Quote(value)
+	}
+	for _, r := range value {
+		if !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
+
+type keyValueDecoder struct {
+	lines     *bufio.Scanner
+	line      int
+	offset    int64  // where the line after the one scanned last starts
+	next      string // a line read too early - the "- " that starts the next salutation
+	nextStart int64  // where that line starts
+	hasNext   bool
+	records   int
+	first     int // the line the record Decode read last starts on
+	stickyError
+}
+
+func (decoder *keyValueDecoder) Position() (record, line int) {
+	return decoder.records, decoder.first
+}
+
+func (decoder *keyValueDecoder) Mark() Mark {
+	if decoder.hasNext {
+		return Mark{Offset: decoder.nextStart, Records: decoder.records, Lines: decoder.line - 1}
+	}
+	return Mark{Offset: decoder.offset, Records: decoder.records, Lines: decoder.line}
+}
+
+// scanLine is bufio.ScanLines counting the bytes it moves past
+func (decoder *keyValueDecoder) scanLine(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	advance, token, err = bufio.ScanLines(data, atEOF)
+	decoder.offset += int64(advance)
+	return advance, token, err
+}
+
+// readLine returns the next line, or false at the end of the input
+func (decoder *keyValueDecoder) readLine() (string, bool) {
+	if decoder.hasNext {
+		decoder.hasNext = false
+		return decoder.next, true
+	}
+	start := decoder.offset
+	if !decoder.lines.Scan() {
+		return "", false
+	}
+	decoder.line++
+	decoder.nextStart = start
+	return decoder.lines.Text(), true
+}
+
+func (decoder *keyValueDecoder) Decode() (salutation core.Salutation, err error) {
+	if decoder.err != nil {
+		return salutation, decoder.err
+	}
+	var (
+		started   bool
+		recordErr *DecodeError
+		seen      = make(map[string]bool)
+	)
+	for {
+		text, ok := decoder.readLine()
+		if !ok {
+			if err := decoder.lines.Err(); err != nil {
+				return salutation, decoder.fail(fmt.Errorf("codec: %w", err))
+			}
+			break
+		}
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || trimmed == "---" {
+			if started {
+				break
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if trimmed == "-" || strings.HasPrefix(trimmed, "- ") {
+			if started {
+				decoder.next, decoder.hasNext = text, true
+				break
+			}
+			trimmed = strings.TrimSpace(trimmed[1:])
+		}
+		if !started {
+			started, decoder.first = true, decoder.line
+			decoder.records++
+		}
+		if recordErr != nil || trimmed == "" {
+			// after the first problem the rest of the salutation is only read to find where it ends
+			continue
+		}
+		if err := setKeyValue(&salutation, trimmed, seen); err != nil {
+			recordErr = &DecodeError{Format: "keyvalue", Record: decoder.records, Line: decoder.line, Err: err}
+		}
+	}
+	if !started {
+		return salutation, decoder.fail(io.EOF)
+	}
+	if recordErr != nil {
+		return core.Salutation{}, recordErr
+	}
+	return salutation, nil
+}
+
+// setKeyValue parses one "key: value" line into the salutation
+func setKeyValue(salutation *core.Salutation, text string, seen map[string]bool) error {
+	key, value, found := strings.Cut(text, ":")
+	if !found {
+		return fmt.Errorf("%q is not a key: value line", text)
+	}
+	f, exists := findField(key, nil)
+	if !exists {
+		return fmt.Errorf("%w %q", ErrUnknownField, strings.TrimSpace(key))
+	}
+	if seen[f.name] {
+		return fmt.Errorf("%s is set more than once", f.name)
+	}
+	seen[f.name] = true
+	value, err := unquoteValue(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.name, err)
+	}
+	*f.get(salutation) = value
+	return nil
+}
+
+// unquoteValue reads a value the way quoteValue writes it, and also takes single quotes and trailing comments
+func unquoteValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		end := closingQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("no closing quote in %s", value)
+		}
+		unquoted, err := strconv.Unquote(value[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("bad quoting in %s", value)
+		}
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %q after the closing quote", rest)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(value, "'"):
+		// in single quotes a quote is written twice and nothing else is escaped
+		var unquoted strings.Builder
+		for i := 1; i < len(value); i++ {
+			if value[i] != '\'' {
+				unquoted.WriteByte(value[i])
+				continue
+			}
+			if i+1 < len(value) && value[i+1] == '\'' {
+				unquoted.WriteByte('\'')
+				i++
+				continue
+			}
+			if rest := strings.TrimSpace(value[i+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return "", fmt.Errorf("unexpected %q after the closing quote", rest)
+			}
+			return unquoted.String(), nil
+		}
+		return "", fmt.Errorf("no closing quote in %s", value)
+	default:
+		if before, _, found := strings.Cut(value, " #"); found {
+			value = strings.TrimSpace(before)
+		}
+		return value, nil
+	}
+}
+
+// closingQuote finds the double quote that ends the string value starts with, skipping escaped quotes
+func closingQuote(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,198 @@
+package codec
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// XML wraps the salutations in a <salutations> element. The name and locale are attributes:
+//
+//	<salutations>
+//		<salutation name="Annica" locale="en">
+//			<casualGreeting>Howdy</casualGreeting>
+//		</salutation>
+//	</salutations>
+//
+// The decoder reads every <salutation> element wherever it is, so a file holding a single <salutation> works too.
+// An element or attribute inside <salutation> that isn't a field is an ErrUnknownField
+var XML Codec = xmlCodec{}
+
+func init() {
+	register(XML)
+}
+
+const (
+	xmlRoot       = "salutations"
+	xmlSalutation = "salutation"
+)
+
+type xmlCodec struct{}
+
+func (xmlCodec) Name() string         { return "xml" }
+func (xmlCodec) Extensions() []string { return []string{".xml"} }
+
+func (xmlCodec) NewEncoder(w io.Writer) Encoder {
+	return &xmlEncoder{w: w, xml: xml.NewEncoder(w)}
+}
+
+func (xmlCodec) NewDecoder(r io.Reader) Decoder {
+	return &xmlDecoder{xml: xml.NewDecoder(r)}
+}
+
+type xmlEncoder struct {
+	w       io.Writer
+	xml     *xml.Encoder
+	started bool
+}
+
+func (encoder *xmlEncoder) start() error {
+	if encoder.started {
+		return nil
+	}
+	encoder.started = true
+	if _, err := io.WriteString(encoder.w, xml.Header); err != nil {
+		return err
+	}
+	encoder.xml.Indent("", "\t")
+	return encoder.xml.EncodeToken(xml.StartElement{Name: xml.Name{Local: xmlRoot}})
+}
+
+func (encoder *xmlEncoder) Encode(salutation core.Salutation) error {
+	if err := encoder.start(); err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	if err := encoder.xml.EncodeElement(salutation, xml.StartElement{Name: xml.Name{Local: xmlSalutation}}); err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	return nil
+}
+
+func (encoder *xmlEncoder) Close() error {
+	err := encoder.start()
+	if err == nil {
+		err = encoder.xml.EncodeToken(xml.EndElement{Name: xml.Name{Local: xmlRoot}})
+	}
+	if err == nil {
+		err = encoder.xml.Flush()
+	}
+	if err == nil {
+		_, err = io.WriteString(encoder.w, "\n")
+	}
+	if err != nil {
+		return fmt.Errorf("codec: %w", err)
+	}
+	return nil
+}
+
+// xmlRecord catches anything in a <salutation> that core.Salutation doesn't have a field for
+type xmlRecord struct {
+	core.Salutation
+	UnknownElements   []struct{ XMLName xml.Name } `xml:",any"`
+	UnknownAttributes []xml.Attr                   `xml:",any,attr"`
+}
+
+type xmlDecoder struct {
+	xml      *xml.Decoder
+	base     int64    // the offset in the whole input of xml.Decoder's offset 0
+	lineBase int      // the lines in the whole input before xml.Decoder's line 1
+	open     []string // the elements around the next <salutation>, outermost first
+	records  int
+	line     int
+	stickyError
+}
+
+func (decoder *xmlDecoder) Position() (record, line int) {
+	return decoder.records, decoder.line
+}
+
+// Mark holds the names of the elements still open, so a resumed decoder can open them again before it carries on
+func (decoder *xmlDecoder) Mark() Mark {
+	line, _ := decoder.xml.InputPos()
+	return Mark{Offset: decoder.base + decoder.xml.InputOffset(), Records: decoder.records, Lines: decoder.lineBase + line - 1, State: strings.Join(decoder.open, " ")}
+}
+
+// The elements are opened again by their local names, so resuming inside an element with a namespace prefix
+// fails at its end tag
+func (xmlCodec) resume(r io.Reader, mark Mark) (Decoder, error) {
+	var prefix strings.Builder
+	for _, name := range strings.Fields(mark.State) {
+		// anything that isn't a single element name would change what the prefix means
+		token, err := xml.NewDecoder(strings.NewReader("<" + name + "/>")).Token()
+		if start, isStart := token.(xml.StartElement); err != nil || !isStart || start.Name.Local != name {
+			return nil, fmt.Errorf("%w for xml", ErrBadMark)
+		}
+		prefix.WriteString("<" + name + ">")
+	}
+	// Decode reads the start elements in the prefix, which puts them back in open
+	return &xmlDecoder{
+		xml:      xml.NewDecoder(io.MultiReader(strings.NewReader(prefix.String()), r)),
+		base:     mark.Offset - int64(prefix.Len()),
+		lineBase: mark.Lines,
+		records:  mark.Records,
+	}, nil
+}
+
+// at returns the line in the whole input of a line counted by xml.Decoder
+func (decoder *xmlDecoder) at(line int) int {
+	return decoder.lineBase + line
+}
+
+func (decoder *xmlDecoder) Decode() (salutation core.Salutation, err error) {
+	if decoder.err != nil {
+		return salutation, decoder.err
+	}
+	for {
+		line, _ := decoder.xml.InputPos()
+		token, err := decoder.xml.Token()
+		if err == io.EOF {
+			return salutation, decoder.fail(io.EOF)
+		}
+		if err != nil {
+			var syntaxError *xml.SyntaxError
+			if errors.As(err, &syntaxError) {
+				line, err = syntaxError.Line, errors.New(syntaxError.Msg)
+			}
+			return salutation, decoder.fail(&DecodeError{Format: "xml", Record: decoder.records, Line: decoder.at(line), Err: err})
+		}
+		if _, isEnd := token.(xml.EndElement); isEnd && len(decoder.open) > 0 {
+			decoder.open = decoder.open[:len(decoder.open)-1]
+		}
+		start, isStart := token.(xml.StartElement)
+		if !isStart {
+			continue
+		}
+		if start.Name.Local != xmlSalutation {
+			decoder.open = append(decoder.open, start.Name.Local)
+			continue
+		}
+		decoder.records++
+		line, _ = decoder.xml.InputPos()
+		line = decoder.at(line)
+		decoder.line = line
+		var record xmlRecord
+		if err := decoder.xml.DecodeElement(&record, &start); err != nil {
+			var syntaxError *xml.SyntaxError
+			if errors.As(err, &syntaxError) {
+				return salutation, decoder.fail(&DecodeError{Format: "xml", Record: decoder.records, Line: decoder.at(syntaxError.Line), Err: errors.New(syntaxError.Msg)})
+			}
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return salutation, decoder.fail(&DecodeError{Format: "xml", Record: decoder.records, Line: line, Err: err})
+			}
+			return salutation, &DecodeError{Format: "xml", Record: decoder.records, Line: line, Err: err}
+		}
+		if len(record.UnknownElements) > 0 {
+			return salutation, &DecodeError{Format: "xml", Record: decoder.records, Line: line,
+				Err: fmt.Errorf("%w <%s>", ErrUnknownField, record.UnknownElements[0].XMLName.Local)}
+		}
+		if len(record.UnknownAttributes) > 0 {
+			return salutation, &DecodeError{Format: "xml", Record: decoder.records, Line: line,
+				Err: fmt.Errorf("%w %s=", ErrUnknownField, record.UnknownAttributes[0].Name.Local)}
+		}
+		return record.Salutation, nil
+	}
+}
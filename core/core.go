@@ -0,0 +1,104 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/greeting"
+)
+
+// The course grew two unrelated Salutation types - greeting.Salutation{Name, Greeting} and
+// goInterfaces.Salutation{Name, CasualGreeting, FormalGreeting}. Salutation in this package is the one model
+// the rest of learngo works with, and the conversion functions below turn either of the legacy types into it (and back).
+
+// DefaultLocale is the locale used when a Salutation doesn't specify one
+const DefaultLocale = greeting.FallbackLocale
+
+// Salutation describes how to greet a single person
+// The struct tags name the fields in JSON and XML, and the codec package uses the same names for CSV columns and keys
+type Salutation struct {
+	Name           string `json:"name" xml:"name,attr"`
+	CasualGreeting string `json:"casualGreeting,omitempty" xml:"casualGreeting,omitempty"`
+	FormalGreeting string `json:"formalGreeting,omitempty" xml:"formalGreeting,omitempty"`
+	Prefix         string `json:"prefix,omitempty" xml:"prefix,omitempty"`      // honorific such as "Ms" or "Dr" - used when greeting formally
+	Locale         string `json:"locale,omitempty" xml:"locale,attr,omitempty"` // language tag such as "en" or "es-MX"
+}
+
+// Salutations is a named type representing a slice of Salutations
+type Salutations []Salutation
+
+// Greeting returns the formal or casual greeting. If only one of them is set it is used for both
+func (salutation Salutation) Greeting(isFormal bool) string {
+	if isFormal && salutation.FormalGreeting != "" || salutation.CasualGreeting == "" {
+		return salutation.FormalGreeting
+	}
+	return salutation.CasualGreeting
+}
+
+// FormalName returns the name with its prefix in front of it - "Ms Annica"
+func (salutation Salutation) FormalName() string {
+	return strings.TrimSpace(strings.TrimSpace(salutation.Prefix) + " " + salutation.Name)
+}
+
+// Message builds the complete greeting - "Howdy, Annica" or "Hello, Ms Annica"
+func (salutation Salutation) Message(isFormal bool) string {
+	if isFormal {
+		return salutation.Greeting(true) + ", " + salutation.FormalName()
+	}
+	return salutation.Greeting(false) + ", " + salutation.Name
+}
+
+// LocaleOrDefault returns the locale, or DefaultLocale when none is set
+func (salutation Salutation) LocaleOrDefault() string {
+	if salutation.Locale == "" {
+		return DefaultLocale
+	}
+	return salutation.Locale
+}
+
+// FromGreeting converts a greeting.Salutation. It only has one greeting, so it becomes both the casual and formal greeting
+func FromGreeting(s greeting.Salutation) Salutation {
+	return Salutation{Name: s.Name, CasualGreeting: s.Greeting, FormalGreeting: s.Greeting, Locale: s.Locale}
+}
+
+// ToGreeting converts back to a greeting.Salutation using either the formal or the casual greeting
+func (salutation Salutation) ToGreeting(isFormal bool) greeting.Salutation {
+	return greeting.Salutation{Name: salutation.Name, Greeting: salutation.Greeting(isFormal), Locale: salutation.Locale}
+}
+
+// FromInterfaces converts a goInterfaces.Salutation
+func FromInterfaces(s goInterfaces.Salutation) Salutation {
+	return Salutation{Name: s.Name, CasualGreeting: s.CasualGreeting, FormalGreeting: s.FormalGreeting}
+}
+
+// ToInterfaces converts back to a goInterfaces.Salutation. The prefix and locale have nowhere to go and are dropped
+func (salutation Salutation) ToInterfaces() goInterfaces.Salutation {
+	return goInterfaces.Salutation{
+		Name:           salutation.Name,
+		CasualGreeting: salutation.CasualGreeting,
+		FormalGreeting: salutation.FormalGreeting,
+	}
+}
+
+// FromSalutations converts every member of a goInterfaces.Salutations slice
+func FromSalutations(salutations goInterfaces.Salutations) (result Salutations) {
+	result = make(Salutations, len(salutations))
+	for i, s := range salutations {
+		result[i] = FromInterfaces(s)
+	}
+	return
+}
+
+// ToInterfaces converts every member back to a goInterfaces.Salutations slice
+func (salutations Salutations) ToInterfaces() (result goInterfaces.Salutations) {
+	result = make(goInterfaces.Salutations, len(salutations))
+	for i, s := range salutations {
+		result[i] = s.ToInterfaces()
+	}
+	return
+}
+
+// VendSalutations produces the same starter salutations as goInterfaces.VendSalutations, as core Salutations
+func VendSalutations() Salutations {
+	return FromSalutations(goInterfaces.VendSalutations())
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/greeting"
+)
+
+func TestGreetingRoundTrip(t *testing.T) {
+	for _, legacy := range []greeting.Salutation{
+		{Name: "Annica", Greeting: "Howdy", Locale: "es-MX"},
+		{Name: "Bob", Greeting: "Hey"},
+		{Name: "Nobody"},
+		{},
+	} {
+		salutation := FromGreeting(legacy)
+		if salutation.CasualGreeting != legacy.Greeting || salutation.FormalGreeting != legacy.Greeting || salutation.Prefix != "" {
+			t.Errorf("FromGreeting(%+v) = %+v, want the greeting in both places", legacy, salutation)
+		}
+		for _, isFormal := range []bool{false, true} {
+			if back := salutation.ToGreeting(isFormal); back != legacy {
+				t.Errorf("ToGreeting(%t) of %+v = %+v, want %+v", isFormal, salutation, back, legacy)
+			}
+		}
+	}
+	both := Salutation{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello", Prefix: "Ms", Locale: "en"}
+	if got := both.ToGreeting(true); got != (greeting.Salutation{Name: "Annica", Greeting: "Hello", Locale: "en"}) {
+		t.Errorf("ToGreeting(true) = %+v", got)
+	}
+	if got := both.ToGreeting(false); got.Greeting != "Howdy" {
+		t.Errorf("ToGreeting(false) = %+v", got)
+	}
+}
+
+func TestInterfacesRoundTrip(t *testing.T) {
+	legacy := goInterfaces.VendSalutations()
+	legacy = append(legacy, goInterfaces.Salutation{}, goInterfaces.Salutation{Name: "Only", FormalGreeting: "Good day"})
+	converted := FromSalutations(legacy)
+	if len(converted) != len(legacy) {
+		t.Fatalf("FromSalutations gave %d salutations, want %d", len(converted), len(legacy))
+	}
+	for i, s := range legacy {
+		if converted[i] != FromInterfaces(s) {
+			t.Errorf("FromSalutations[%d] = %+v, want %+v", i, converted[i], FromInterfaces(s))
+		}
+	}
+	if back := converted.ToInterfaces(); !slices.Equal(back, legacy) {
+		t.Errorf("the round trip gave %+v, want %+v", back, legacy)
+	}
+	// the prefix and locale have nowhere to go
+	full := Salutation{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello", Prefix: "Ms", Locale: "en"}
+	want := goInterfaces.Salutation{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello"}
+	if got := full.ToInterfaces(); got != want {
+		t.Errorf("ToInterfaces = %+v, want %+v", got, want)
+	}
+	if got := FromSalutations(nil); got == nil || len(got) != 0 {
+		t.Errorf("FromSalutations(nil) = %#v, want an empty slice", got)
+	}
+	if got := VendSalutations(); !slices.Equal(got.ToInterfaces(), goInterfaces.VendSalutations()) {
+		t.Errorf("VendSalutations = %+v", got)
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	tests := []struct {
+		salutation     Salutation
+		casual, formal string
+		formalName     string
+		locale         string
+	}{
+		{Salutation{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello", Prefix: "Ms", Locale: "es"}, "Howdy", "Hello", "Ms Annica", "es"},
+		{Salutation{Name: "Annica", CasualGreeting: "Howdy"}, "Howdy", "Howdy", "Annica", DefaultLocale},
+		{Salutation{Name: "Annica", FormalGreeting: "Hello"}, "Hello", "Hello", "Annica", DefaultLocale},
+		{Salutation{Name: "Annica"}, "", "", "Annica", DefaultLocale},
+		{Salutation{Name: "Annica", Prefix: "  Dr  "}, "", "", "Dr Annica", DefaultLocale},
+		{Salutation{Prefix: "Dr"}, "", "", "Dr", DefaultLocale},
+	}
+	for _, test := range tests {
+		s := test.salutation
+		if got := s.Greeting(false); got != test.casual {
+			t.Errorf("%+v: Greeting(false) = %q, want %q", s, got, test.casual)
+		}
+		if got := s.Greeting(true); got != test.formal {
+			t.Errorf("%+v: Greeting(true) = %q, want %q", s, got, test.formal)
+		}
+		if got := s.FormalName(); got != test.formalName {
+			t.Errorf("%+v: FormalName = %q, want %q", s, got, test.formalName)
+		}
+		if got := s.LocaleOrDefault(); got != test.locale {
+			t.Errorf("%+v: LocaleOrDefault = %q, want %q", s, got, test.locale)
+		}
+	}
+	s := Salutation{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello", Prefix: "Ms "}
+	if got := s.Message(true); got != "Hello, Ms Annica" {
+		t.Errorf("Message(true) = %q", got)
+	}
+	if got := s.Message(false); got != "Howdy, Annica" {
+		t.Errorf("Message(false) = %q", got)
+	}
+}
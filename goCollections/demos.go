@@ -0,0 +1,62 @@
+package goCollections
+
+import (
+	"io"
+
+	"github.com/annicaburns/learngo/registry"
+	"github.com/annicaburns/learngo/store"
+)
+
+// Register the collection demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "goCollections.BasicArray",
+		Description: "declare an array (prints nothing)",
+		Run: func(w io.Writer, args registry.Args) error {
+			BasicArray()
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goCollections.PrintFilteredSlice",
+		Description: "filter a slice by position with [1:]",
+		Params:      []registry.Param{registry.RosterParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			roster, err := store.LoadRoster(args.String("roster"), BasicSlice())
+			if err != nil {
+				return err
+			}
+			PrintFilteredSliceOf(w, roster)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goCollections.PrintBiggerSlice",
+		Description: "grow a slice with append",
+		Run: func(w io.Writer, args registry.Args) error {
+			PrintBiggerSliceTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goCollections.PrintSmallerSlice",
+		Description: "delete from the middle of a slice with append, leaving the original alone",
+		Run: func(w io.Writer, args registry.Args) error {
+			PrintSmallerSliceTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goCollections.SliceHelpers",
+		Description: "filter, map, sort, group and chunk a roster with generic helpers",
+		Params:      []registry.Param{registry.RosterParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			roster, err := store.LoadRoster(args.String("roster"), BasicSlice())
+			if err != nil {
+				return err
+			}
+			SliceHelpersOf(w, roster)
+			return nil
+		},
+	})
+}
@@ -2,8 +2,10 @@ package goCollections
 
 import (
 	"fmt"
+	"io"
+	"os"
 
-	"github.com/annicaburns/learngo/greeting"
+	"github.com/annicaburns/learngo/core"
 )
 
 // BasicArray demonstrates the characteristics and semantics of a GO array
@@ -24,7 +26,7 @@ func BasicArray() {
 // Fixed size, but can be re-allocated with append to make it grow
 // You can make a slice of a slice, and the new slice will still point to the underlying data in the original
 // https://golang.org/doc/effective_go.html#slices
-func BasicSlice() (salutationSlice []greeting.Salutation) {
+func BasicSlice() (salutationSlice []core.Salutation) {
 	//var items []int = make([]int,3,5) - initial slice has 3 items (it's length), but it has a capacity of 5
 
 	// var items = make([]int, 3) // length and capacity are the same - both set to 3
@@ -34,10 +36,10 @@ func BasicSlice() (salutationSlice []greeting.Salutation) {
 
 	// items := []int{1, 2, 3}
 
-	salutationSlice = []greeting.Salutation{
-		{Name: "Annica", Greeting: "Hello"},
-		{Name: "Mitchel", Greeting: "Howdy"},
-		{Name: "Joline", Greeting: "Welcome"},
+	salutationSlice = []core.Salutation{
+		{Name: "Annica", CasualGreeting: "Hello"},
+		{Name: "Mitchel", CasualGreeting: "Howdy"},
+		{Name: "Joline", CasualGreeting: "Welcome"},
 	}
 
 	return
@@ -47,7 +49,7 @@ func BasicSlice() (salutationSlice []greeting.Salutation) {
 // This operation includes the start index but excludes the end index - [1:2] only includes the item at index 1
 // [:2] will include Annica and Mitchel
 // [1:] will include Mitchel and Joline
-func SlicingASlice(startingSlice []greeting.Salutation) (finalSlice []greeting.Salutation) {
+func SlicingASlice(startingSlice []core.Salutation) (finalSlice []core.Salutation) {
 	// finalSlice = startingSlice[1:2]
 	finalSlice = startingSlice[1:]
 	return
@@ -55,14 +57,24 @@ func SlicingASlice(startingSlice []greeting.Salutation) (finalSlice []greeting.S
 
 // PrintFilteredSlice demonstrates SlicingASlice
 func PrintFilteredSlice() {
-	var finalSlice = SlicingASlice(BasicSlice())
-	fmt.Println(finalSlice)
-	fmt.Println(len(finalSlice))
+	PrintFilteredSliceTo(os.Stdout)
 }
 
-func appendingASlice(startingSlice []greeting.Salutation) (finalSlice []greeting.Salutation) {
+// PrintFilteredSliceTo is PrintFilteredSlice writing to w
+func PrintFilteredSliceTo(w io.Writer) {
+	PrintFilteredSliceOf(w, BasicSlice())
+}
+
+// PrintFilteredSliceOf is PrintFilteredSlice filtering any roster of salutations
+func PrintFilteredSliceOf(w io.Writer, salutations []core.Salutation) {
+	var finalSlice = SlicingASlice(salutations)
+	fmt.Fprintln(w, finalSlice)
+	fmt.Fprintln(w, len(finalSlice))
+}
+
+func appendingASlice(startingSlice []core.Salutation) (finalSlice []core.Salutation) {
 	// Can add a single element to a slice
-	var biggerSlice = append(startingSlice, greeting.Salutation{Name: "Tammy", Greeting: "Salud"})
+	var biggerSlice = append(startingSlice, core.Salutation{Name: "Tammy", CasualGreeting: "Salud"})
 	var filteredSlice = biggerSlice[3:]
 	// Or can add a slice to a slice
 	finalSlice = append(filteredSlice, filteredSlice...)
@@ -71,18 +83,33 @@ func appendingASlice(startingSlice []greeting.Salutation) (finalSlice []greeting
 
 // PrintBiggerSlice demonstrates appendingASlice
 func PrintBiggerSlice() {
+	PrintBiggerSliceTo(os.Stdout)
+}
+
+// PrintBiggerSliceTo is PrintBiggerSlice writing to w
+func PrintBiggerSliceTo(w io.Writer) {
 	var finalSlice = appendingASlice(BasicSlice())
-	fmt.Println(finalSlice)
+	fmt.Fprintln(w, finalSlice)
 }
 
-func deletingASlice(startingSlice []greeting.Salutation) (finalSlice []greeting.Salutation) {
+func deletingASlice(startingSlice []core.Salutation) (finalSlice []core.Salutation) {
 	// use append to cobble together all the elements you want to keep, omitting the ones you don't
-	finalSlice = append(startingSlice[:1], startingSlice[2:]...)
+	// append(startingSlice[:1], startingSlice[2:]...) would write into startingSlice's backing array and change the caller's slice,
+	// so start from a new slice - RemoveAt does the same for any slice
+	finalSlice = append(make([]core.Salutation, 0, len(startingSlice)-1), startingSlice[:1]...)
+	finalSlice = append(finalSlice, startingSlice[2:]...)
 	return
 }
 
 // PrintSmallerSlice demonstrates deletingASlice
 func PrintSmallerSlice() {
-	var finalSlice = deletingASlice(BasicSlice())
-	fmt.Println(finalSlice)
+	PrintSmallerSliceTo(os.Stdout)
+}
+
+// PrintSmallerSliceTo is PrintSmallerSlice writing to w
+func PrintSmallerSliceTo(w io.Writer) {
+	var startingSlice = BasicSlice()
+	var finalSlice = deletingASlice(startingSlice)
+	fmt.Fprintln(w, finalSlice)
+	fmt.Fprintln(w, "unchanged:", startingSlice)
 }
@@ -0,0 +1,169 @@
+package goCollections
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// Generic helpers for working with slices such as core.Salutations.
+// None of them changes the slice it is given, and a slice they return never shares a backing array with it -
+// appending to or writing into a result can't reach the caller's elements. That is the bug deletingASlice used to have:
+// append(startingSlice[:1], startingSlice[2:]...) writes over startingSlice[1], so the caller's
+// [Annica Mitchel Joline] silently became [Annica Joline Joline].
+
+// ErrIndexOutOfRange is returned by RemoveAt and InsertAt for an index outside the slice
+var ErrIndexOutOfRange = errors.New("goCollections: index out of range")
+
+// Filter returns the elements keep returns true for
+func Filter[S ~[]E, E any](s S, keep func(E) bool) S {
+	result := S{}
+	for _, element := range s {
+		if keep(element) {
+			result = append(result, element)
+		}
+	}
+	return result
+}
+
+// Map returns the result of calling transform on every element
+func Map[E, R any](s []E, transform func(E) R) []R {
+	result := make([]R, len(s))
+	for i, element := range s {
+		result[i] = transform(element)
+	}
+	return result
+}
+
+// Reduce combines the elements into one value, starting from initial - Reduce(s, 0, add) is the sum
+func Reduce[E, A any](s []E, initial A, combine func(A, E) A) A {
+	result := initial
+	for _, element := range s {
+		result = combine(result, element)
+	}
+	return result
+}
+
+// RemoveAt returns a copy of s without the element at index i
+func RemoveAt[S ~[]E, E any](s S, i int) (S, error) {
+	if i < 0 || i >= len(s) {
+		return nil, fmt.Errorf("%w: %d in a slice of %d", ErrIndexOutOfRange, i, len(s))
+	}
+	result := make(S, 0, len(s)-1)
+	result = append(result, s[:i]...)
+	return append(result, s[i+1:]...), nil
+}
+
+// InsertAt returns a copy of s with values inserted before index i. i may be len(s), which appends them
+func InsertAt[S ~[]E, E any](s S, i int, values ...E) (S, error) {
+	if i < 0 || i > len(s) {
+		return nil, fmt.Errorf("%w: %d in a slice of %d", ErrIndexOutOfRange, i, len(s))
+	}
+	result := make(S, 0, len(s)+len(values))
+	result = append(result, s[:i]...)
+	result = append(result, values...)
+	return append(result, s[i:]...), nil
+}
+
+// Chunk splits s into slices of size elements - the last one has whatever is left over.
+// Unlike slices.Chunk, each chunk is a copy. It panics if size is less than 1
+func Chunk[S ~[]E, E any](s S, size int) []S {
+	if size < 1 {
+		panic("goCollections: Chunk size must be at least 1")
+	}
+	chunks := make([]S, 0, (len(s)+size-1)/size)
+	for start := 0; start < len(s); start += size {
+		end := min(start+size, len(s))
+		chunks = append(chunks, slices.Clone(s[start:end]))
+	}
+	return chunks
+}
+
+// Partition splits s into the elements match returns true for and the rest, keeping their order
+func Partition[S ~[]E, E any](s S, match func(E) bool) (matched, rest S) {
+	matched, rest = S{}, S{}
+	for _, element := range s {
+		if match(element) {
+			matched = append(matched, element)
+		} else {
+			rest = append(rest, element)
+		}
+	}
+	return matched, rest
+}
+
+// UniqueBy returns the first element for each key, in their original order
+func UniqueBy[S ~[]E, E any, K comparable](s S, key func(E) K) S {
+	seen := make(map[K]bool, len(s))
+	return Filter(s, func(element E) bool {
+		k := key(element)
+		if seen[k] {
+			return false
+		}
+		seen[k] = true
+		return true
+	})
+}
+
+// SortBy returns a copy of s sorted by key. Elements with the same key keep their order
+func SortBy[S ~[]E, E any, K cmp.Ordered](s S, key func(E) K) S {
+	result := slices.Clone(s)
+	if result == nil {
+		result = S{}
+	}
+	slices.SortStableFunc(result, func(a, b E) int { return cmp.Compare(key(a), key(b)) })
+	return result
+}
+
+// GroupBy collects the elements with the same key, keeping their order within each group
+func GroupBy[S ~[]E, E any, K comparable](s S, key func(E) K) map[K]S {
+	groups := make(map[K]S)
+	for _, element := range s {
+		k := key(element)
+		groups[k] = append(groups[k], element)
+	}
+	return groups
+}
+
+// SliceHelpers demonstrates the generic helpers on a roster
+func SliceHelpers() {
+	SliceHelpersTo(os.Stdout)
+}
+
+// SliceHelpersTo is SliceHelpers writing to w
+func SliceHelpersTo(w io.Writer) {
+	SliceHelpersOf(w, BasicSlice())
+}
+
+// SliceHelpersOf is SliceHelpers working on any roster of salutations
+func SliceHelpersOf(w io.Writer, salutations core.Salutations) {
+	fmt.Fprintln(w, "names:", Map(salutations, func(s core.Salutation) string { return s.Name }))
+	fmt.Fprintln(w, "sorted by greeting:", SortBy(salutations, func(s core.Salutation) string { return s.CasualGreeting }))
+	short, long := Partition(salutations, func(s core.Salutation) bool { return len(s.Name) <= 6 })
+	fmt.Fprintln(w, "short names:", short, "long names:", long)
+	letters := Reduce(salutations, 0, func(total int, s core.Salutation) int { return total + len(s.Name) })
+	fmt.Fprintln(w, "letters in every name:", letters)
+	initialOf := func(s core.Salutation) string {
+		for _, initial := range s.Name {
+			return strings.ToUpper(string(initial))
+		}
+		return ""
+	}
+	itself := func(initial string) string { return initial }
+	byInitial := GroupBy(salutations, initialOf)
+	for _, initial := range SortBy(UniqueBy(Map(salutations, initialOf), itself), itself) {
+		fmt.Fprintf(w, "names starting with %s: %d\n", initial, len(byInitial[initial]))
+	}
+	for _, chunk := range Chunk(salutations, 2) {
+		fmt.Fprintln(w, "chunk:", chunk)
+	}
+	if smaller, err := RemoveAt(salutations, 1); err == nil {
+		fmt.Fprintln(w, "without the second:", smaller, "- the roster is still", salutations)
+	}
+}
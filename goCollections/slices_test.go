@@ -0,0 +1,191 @@
+package goCollections
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+func TestDeletingASliceLeavesTheCallersSliceAlone(t *testing.T) {
+	startingSlice := []core.Salutation{{Name: "Annica"}, {Name: "Mitchel"}, {Name: "Joline"}}
+	before := slices.Clone(startingSlice)
+	finalSlice := deletingASlice(startingSlice)
+	if want := []core.Salutation{{Name: "Annica"}, {Name: "Joline"}}; !slices.Equal(finalSlice, want) {
+		t.Errorf("deletingASlice = %v, want %v", finalSlice, want)
+	}
+	if !slices.Equal(startingSlice, before) {
+		t.Fatalf("deletingASlice changed the caller's slice from %v to %v", before, startingSlice)
+	}
+	// the result has an array of its own, so writing to it can't reach the caller either
+	finalSlice[0].Name = "Nic"
+	finalSlice = append(finalSlice, core.Salutation{Name: "Bob"})
+	if !slices.Equal(startingSlice, before) {
+		t.Errorf("writing to the result changed the caller's slice to %v", startingSlice)
+	}
+}
+
+var (
+	none   []int
+	empty  = []int{}
+	single = []int{7}
+	many   = []int{1, 2, 3, 4, 5}
+)
+
+func isOdd(n int) bool { return n%2 == 1 }
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want []int
+	}{
+		{"nil", none, nil},
+		{"empty", empty, nil},
+		{"single kept", single, []int{7}},
+		{"single dropped", []int{2}, nil},
+		{"many", many, []int{1, 3, 5}},
+	}
+	for _, test := range tests {
+		in := slices.Clone(test.in)
+		got := Filter(in, isOdd)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: Filter = %v, want %v", test.name, got, test.want)
+		}
+		if got == nil {
+			t.Errorf("%s: Filter returned nil rather than an empty slice", test.name)
+		}
+		if len(got) > 0 {
+			got[0] = -1
+			if !slices.Equal(in, test.in) {
+				t.Errorf("%s: writing to Filter's result changed its input", test.name)
+			}
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want []string
+	}{
+		{"nil", none, nil},
+		{"empty", empty, nil},
+		{"single", single, []string{"7"}},
+		{"many", many, []string{"1", "2", "3", "4", "5"}},
+	}
+	for _, test := range tests {
+		if got := Map(test.in, strconv.Itoa); !slices.Equal(got, test.want) {
+			t.Errorf("%s: Map = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReduce(t *testing.T) {
+	add := func(total, n int) int { return total + n }
+	tests := []struct {
+		name    string
+		in      []int
+		initial int
+		want    int
+	}{
+		{"nil", none, 10, 10},
+		{"empty", empty, 0, 0},
+		{"single", single, 1, 8},
+		{"many", many, 0, 15},
+	}
+	for _, test := range tests {
+		if got := Reduce(test.in, test.initial, add); got != test.want {
+			t.Errorf("%s: Reduce = %d, want %d", test.name, got, test.want)
+		}
+	}
+	// the order is kept - each element is combined with everything before it
+	if got := Reduce(many, "", func(s string, n int) string { return s + strconv.Itoa(n) }); got != "12345" {
+		t.Errorf("Reduce concatenated %q, want 12345", got)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		size int
+		want [][]int
+	}{
+		{"nil", none, 2, nil},
+		{"empty", empty, 2, nil},
+		{"single", single, 2, [][]int{{7}}},
+		{"size one", []int{1, 2}, 1, [][]int{{1}, {2}}},
+		{"exact", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"left over", many, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"bigger than the slice", many, 10, [][]int{{1, 2, 3, 4, 5}}},
+	}
+	for _, test := range tests {
+		in := slices.Clone(test.in)
+		got := Chunk(in, test.size)
+		if !slices.EqualFunc(got, test.want, slices.Equal) {
+			t.Errorf("%s: Chunk(%d) = %v, want %v", test.name, test.size, got, test.want)
+		}
+		// every chunk is a copy
+		for _, chunk := range got {
+			chunk[0] = -1
+			_ = append(chunk, -1)
+		}
+		if !slices.Equal(in, test.in) {
+			t.Errorf("%s: writing to a chunk changed the input to %v", test.name, in)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Chunk with size 0 didn't panic")
+		}
+	}()
+	Chunk(many, 0)
+}
+
+func TestGroupBy(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want map[bool][]int
+	}{
+		{"nil", none, map[bool][]int{}},
+		{"empty", empty, map[bool][]int{}},
+		{"single", single, map[bool][]int{true: {7}}},
+		{"many", many, map[bool][]int{true: {1, 3, 5}, false: {2, 4}}},
+	}
+	for _, test := range tests {
+		got := GroupBy(test.in, isOdd)
+		if got == nil || !maps.EqualFunc(got, test.want, slices.Equal) {
+			t.Errorf("%s: GroupBy = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRemoveAtAndInsertAtCopy(t *testing.T) {
+	in := slices.Clone(many)
+	removed, err := RemoveAt(in, 1)
+	if err != nil || !slices.Equal(removed, []int{1, 3, 4, 5}) {
+		t.Fatalf("RemoveAt = %v, %v", removed, err)
+	}
+	inserted, err := InsertAt(in, 5, 6, 7)
+	if err != nil || !slices.Equal(inserted, []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Fatalf("InsertAt = %v, %v", inserted, err)
+	}
+	removed[0], inserted[0] = -1, -1
+	if !slices.Equal(in, many) {
+		t.Errorf("writing to the results changed the input to %v", in)
+	}
+	for _, i := range []int{-1, 5} {
+		if _, err := RemoveAt(in, i); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("RemoveAt(%d) = %v, want ErrIndexOutOfRange", i, err)
+		}
+	}
+	if _, err := InsertAt(in, 6); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("InsertAt(6) = %v, want ErrIndexOutOfRange", err)
+	}
+}
@@ -0,0 +1,246 @@
+package goConcurrency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/annicaburns/learngo/clock"
+	"github.com/annicaburns/learngo/goInterfaces"
+)
+
+// Channel combinators work on channels of any element type - goInterfaces.Salutation, core.Salutation, string...
+// Each one starts its own goroutines and returns new channels, so they plug together into pipelines:
+//
+//	Batch(ctx, clock.Real, Throttle(ctx, clock.Real, Merge(ctx, a, b, c), time.Second), 10, time.Minute)
+//
+// Batch and Throttle wait using the clock they are given, so a test can hand them a clock.Fake
+// and decide exactly when time passes. A nil clock means clock.Real.
+// Every output channel is closed once its inputs are closed and drained, or as soon as ctx is cancelled -
+// even while an input has nothing to send. Cancelling ctx is how a consumer that stops reading early
+// lets the goroutines behind it exit.
+
+// Merge combines the inputs into one channel, which is closed once every input is closed and drained.
+// Unlike ConcurrencySelect it doesn't stop at the first closed channel - nothing sent on any input is dropped
+func Merge[T any](ctx context.Context, inputs ...<-chan T) <-chan T {
+	output := make(chan T)
+	var wg sync.WaitGroup
+	for _, input := range inputs {
+		wg.Add(1)
+		go func(input <-chan T) {
+			defer wg.Done()
+			for {
+				value, ok := receive(ctx, input)
+				if !ok || !send(ctx, output, value) {
+					return
+				}
+			}
+		}(input)
+	}
+	go func() {
+		wg.Wait()
+		close(output)
+	}()
+	return output
+}
+
+// FanOut shares the values from input between n consumers - each value goes to exactly one of the outputs,
+// whichever is ready first, so a slow consumer simply gets fewer values. An n less than 1 is treated as 1
+func FanOut[T any](ctx context.Context, input <-chan T, n int) []<-chan T {
+	outputs := make([]<-chan T, max(n, 1))
+	for i := range outputs {
+		output := make(chan T)
+		outputs[i] = output
+		go func() {
+			defer close(output)
+			for {
+				value, ok := receive(ctx, input)
+				if !ok || !send(ctx, output, value) {
+					return
+				}
+			}
+		}()
+	}
+	return outputs
+}
+
+// Tee copies every value from input to each of n outputs. A value is only read from input once every output
+// has received the one before it, so all of the outputs must be read - the slowest consumer sets the pace.
+// An n less than 1 is treated as 1, rather than reading every value and sending it nowhere
+func Tee[T any](ctx context.Context, input <-chan T, n int) []<-chan T {
+	n = max(n, 1)
+	channels := make([]chan T, n)
+	outputs := make([]<-chan T, n)
+	for i := range channels {
+		channels[i] = make(chan T)
+		outputs[i] = channels[i]
+	}
+	go func() {
+		defer func() {
+			for _, channel := range channels {
+				close(channel)
+			}
+		}()
+		// select needs its cases written out, so reflect.Select is used to send to however many outputs there are.
+		// The last case waits for ctx to be cancelled
+		cases := make([]reflect.SelectCase, n+1)
+		cases[n] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+		for {
+			value, ok := receive(ctx, input)
+			if !ok {
+				return
+			}
+			for i, channel := range channels {
+				cases[i] = reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(channel), Send: reflect.ValueOf(&value).Elem()}
+			}
+			// send to whichever outputs are ready first, dropping each case once it has the value
+			for remaining := n; remaining > 0; remaining-- {
+				chosen, _, _ := reflect.Select(cases)
+				if chosen == n {
+					return
+				}
+				cases[chosen].Chan = reflect.Value{}
+			}
+		}
+	}()
+	return outputs
+}
+
+// Batch groups values from input into slices of up to size values. A batch is sent when it is full,
+// or when timeout has passed since its first value arrived, so a slow input still gets through.
+// A timeout of 0 only ever sends full batches, plus whatever is left when input closes
+func Batch[T any](ctx context.Context, clk clock.Clock, input <-chan T, size int, timeout time.Duration) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+	if clk == nil {
+		clk = clock.Real
+	}
+	output := make(chan []T)
+	go func() {
+		defer close(output)
+		var batch []T
+		// a nil timer channel never fires, so there is no deadline until a batch is started
+		var deadline <-chan time.Time
+		var timer clock.Timer
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, deadline = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			full := batch
+			batch = nil
+			return send(ctx, output, full)
+		}
+		for {
+			select {
+			case value, ok := <-input:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, value)
+				if len(batch) == 1 && timeout > 0 {
+					timer = clk.NewTimer(timeout)
+					deadline = timer.C()
+				}
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-deadline:
+				timer, deadline = nil, nil
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return output
+}
+
+// Throttle passes values from input on at a rate of no more than one every interval
+func Throttle[T any](ctx context.Context, clk clock.Clock, input <-chan T, interval time.Duration) <-chan T {
+	if clk == nil {
+		clk = clock.Real
+	}
+	output := make(chan T)
+	go func() {
+		defer close(output)
+		var next time.Time
+		for {
+			value, ok := receive(ctx, input)
+			if !ok {
+				return
+			}
+			if wait := clk.Until(next); wait > 0 {
+				timer := clk.NewTimer(wait)
+				select {
+				case <-timer.C():
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+			if !send(ctx, output, value) {
+				return
+			}
+			next = clk.Now().Add(interval)
+		}
+	}()
+	return output
+}
+
+// receive receives a value from channel, reporting false if channel is closed or ctx is cancelled first
+func receive[T any](ctx context.Context, channel <-chan T) (value T, ok bool) {
+	select {
+	case value, ok = <-channel:
+		return value, ok
+	case <-ctx.Done():
+		return value, false
+	}
+}
+
+// send sends value on channel, reporting false if ctx is cancelled first
+func send[T any](ctx context.Context, channel chan<- T, value T) bool {
+	select {
+	case channel <- value:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// MergeChannels demonstrates merging several ChannelGreeter channels, then throttling and batching the result
+func MergeChannels(channels, batchSize int, interval time.Duration) {
+	MergeChannelsTo(os.Stdout, channels, batchSize, interval)
+}
+
+// MergeChannelsTo is MergeChannels writing to w
+func MergeChannelsTo(w io.Writer, channels, batchSize int, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inputs := make([]<-chan goInterfaces.Salutation, channels)
+	for i := range inputs {
+		inputs[i] = goInterfaces.VendSalutations().Channel(ctx)
+	}
+	merged := Merge(ctx, inputs...)
+	if interval > 0 {
+		merged = Throttle(ctx, clock.Real, merged, interval)
+	}
+	for batch := range Batch(ctx, clock.Real, merged, batchSize, time.Second) {
+		names := make([]string, len(batch))
+		for i, salutation := range batch {
+			names[i] = salutation.Name
+		}
+		fmt.Fprintln(w, names)
+	}
+}
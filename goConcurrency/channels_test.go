@@ -0,0 +1,172 @@
+package goConcurrency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/registry"
+)
+
+// numbers returns a channel that sends from, from+1 ... up to but not including to, then closes
+func numbers(from, to int) <-chan int {
+	channel := make(chan int)
+	go func() {
+		defer close(channel)
+		for i := from; i < to; i++ {
+			channel <- i
+		}
+	}()
+	return channel
+}
+
+// drain reads every value from each of the channels at the same time, and returns what each one received
+func drain[T any](channels ...<-chan T) [][]T {
+	received := make([][]T, len(channels))
+	var wg sync.WaitGroup
+	for i, channel := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for value := range channel {
+				received[i] = append(received[i], value)
+			}
+		}()
+	}
+	wg.Wait()
+	return received
+}
+
+func TestMergeDrainsEveryInput(t *testing.T) {
+	checkNoLeaks(t)
+	merged := drain(Merge(context.Background(), numbers(0, 10), numbers(10, 15), numbers(15, 100)))[0]
+	slices.Sort(merged)
+	if len(merged) != 100 || merged[0] != 0 || merged[99] != 99 {
+		t.Errorf("Merge returned %d values, want 0 to 99", len(merged))
+	}
+}
+
+func TestFanOutSendsEachValueOnce(t *testing.T) {
+	checkNoLeaks(t)
+	var all []int
+	for _, received := range drain(FanOut(context.Background(), numbers(0, 100), 4)...) {
+		all = append(all, received...)
+	}
+	slices.Sort(all)
+	if len(all) != 100 || len(slices.Compact(all)) != 100 {
+		t.Errorf("FanOut delivered %d values, want each of 100 once", len(all))
+	}
+}
+
+func TestTeeCopiesEveryValue(t *testing.T) {
+	checkNoLeaks(t)
+	for i, received := range drain(Tee(context.Background(), numbers(0, 50), 3)...) {
+		if len(received) != 50 || received[0] != 0 || received[49] != 49 {
+			t.Errorf("output %d received %d values, want 0 to 49 in order", i, len(received))
+		}
+	}
+}
+
+func TestFanOutAndTeeWithoutOutputs(t *testing.T) {
+	checkNoLeaks(t)
+	for _, n := range []int{0, -1} {
+		outputs := FanOut(context.Background(), numbers(0, 5), n)
+		if len(outputs) != 1 || len(drain(outputs...)[0]) != 5 {
+			t.Errorf("FanOut with n = %d didn't deliver everything to one output", n)
+		}
+		outputs = Tee(context.Background(), numbers(0, 5), n)
+		if len(outputs) != 1 || len(drain(outputs...)[0]) != 5 {
+			t.Errorf("Tee with n = %d didn't deliver everything to one output", n)
+		}
+	}
+}
+
+func TestBatchSendsFullBatchesAndTheRest(t *testing.T) {
+	checkNoLeaks(t)
+	batches := drain(Batch(context.Background(), nil, numbers(0, 10), 4, 0))[0]
+	sizes := make([]int, len(batches))
+	for i, batch := range batches {
+		sizes[i] = len(batch)
+	}
+	if !slices.Equal(sizes, []int{4, 4, 2}) {
+		t.Errorf("batch sizes %v, want [4 4 2]", sizes)
+	}
+}
+
+// TestCombinatorsStopWhileInputIsIdle cancels ctx while the input has nothing to send and is never closed.
+// Each output has to close anyway, and every goroutine behind it has to exit
+func TestCombinatorsStopWhileInputIsIdle(t *testing.T) {
+	checkNoLeaks(t)
+	combinators := map[string]func(ctx context.Context, idle <-chan int) []<-chan int{
+		"Merge":  func(ctx context.Context, idle <-chan int) []<-chan int { return []<-chan int{Merge(ctx, idle, idle)} },
+		"FanOut": func(ctx context.Context, idle <-chan int) []<-chan int { return FanOut(ctx, idle, 3) },
+		"Tee":    func(ctx context.Context, idle <-chan int) []<-chan int { return Tee(ctx, idle, 3) },
+		"Throttle": func(ctx context.Context, idle <-chan int) []<-chan int {
+			return []<-chan int{Throttle(ctx, nil, idle, time.Hour)}
+		},
+		"Batch": func(ctx context.Context, idle <-chan int) []<-chan int {
+			batches := Batch(ctx, nil, idle, 10, time.Hour)
+			count := make(chan int)
+			go func() {
+				defer close(count)
+				for batch := range batches {
+					count <- len(batch)
+				}
+			}()
+			return []<-chan int{count}
+		},
+	}
+	for name, combinator := range combinators {
+		ctx, cancel := context.WithCancel(context.Background())
+		outputs := combinator(ctx, make(chan int))
+		cancel()
+		for i, output := range outputs {
+			if !closedWithin(output) {
+				t.Errorf("%s: output %d is still open a second after ctx was cancelled", name, i)
+			}
+		}
+	}
+}
+
+func TestCombinatorsStopWhenTheConsumerDoes(t *testing.T) {
+	checkNoLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	// a long pipeline whose consumer reads one batch and walks away
+	input := make(chan int)
+	go func() {
+		defer close(input)
+		for i := 0; ; i++ {
+			if !send(ctx, input, i) {
+				return
+			}
+		}
+	}()
+	outputs := Tee(ctx, Merge(ctx, input), 2)
+	batches := Batch(ctx, nil, FanOut(ctx, outputs[0], 2)[0], 3, 0)
+	go drain(outputs[1])
+	if batch := <-batches; len(batch) != 3 {
+		t.Errorf("first batch has %d values, want 3", len(batch))
+	}
+	cancel()
+	if !closedWithin(batches) {
+		t.Error("the pipeline didn't close after ctx was cancelled")
+	}
+}
+
+func TestMergeChannelsDemoRejectsBadChannelCounts(t *testing.T) {
+	for _, channels := range []string{"-1", "0"} {
+		err := registry.Run("goConcurrency.MergeChannels", io.Discard, map[string]string{"channels": channels})
+		if !errors.Is(err, registry.ErrInvalidParam) {
+			t.Errorf("MergeChannels with %s channels = %v, want ErrInvalidParam", channels, err)
+		}
+	}
+	var output strings.Builder
+	if err := registry.Run("goConcurrency.MergeChannels", &output, map[string]string{"channels": "1", "batch": "10"}); err != nil || !strings.Contains(output.String(), "Annica") {
+		t.Errorf("MergeChannels with 1 channel = %v, wrote %q", err, output.String())
+	}
+}
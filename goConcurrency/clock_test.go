@@ -0,0 +1,151 @@
+package goConcurrency
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/clock"
+)
+
+var start = time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+// nothingWithin reports whether channel stays quiet for a moment. A clock.Fake doesn't move on its own,
+// so anything that arrived would have come early rather than late
+func nothingWithin[T any](channel <-chan T) bool {
+	select {
+	case <-channel:
+		return false
+	case <-time.After(20 * time.Millisecond):
+		return true
+	}
+}
+
+func TestBatchTimeoutOnAFakeClock(t *testing.T) {
+	checkNoLeaks(t)
+	fake := clock.NewFake(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	input := make(chan int)
+	batches := Batch(ctx, fake, input, 10, time.Minute)
+
+	input <- 1
+	input <- 2
+	// the timer is started by the first value of a batch
+	fake.BlockUntil(1)
+	fake.Advance(59 * time.Second)
+	if !nothingWithin(batches) {
+		t.Fatal("a batch was sent before its timeout")
+	}
+	fake.Advance(time.Second)
+	if batch := <-batches; len(batch) != 2 {
+		t.Fatalf("the timed out batch has %d values, want 2", len(batch))
+	}
+	// a full batch goes straight away and stops its timer
+	for i := 0; i < 10; i++ {
+		input <- i
+	}
+	if batch := <-batches; len(batch) != 10 {
+		t.Fatalf("the full batch has %d values, want 10", len(batch))
+	}
+	if fake.Waiters() != 0 {
+		t.Errorf("%d timers left running after a full batch", fake.Waiters())
+	}
+	close(input)
+	if !closedWithin(batches) {
+		t.Error("Batch didn't close its output when the input closed")
+	}
+}
+
+func TestThrottleOnAFakeClock(t *testing.T) {
+	checkNoLeaks(t)
+	fake := clock.NewFake(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	input := make(chan int, 3)
+	input <- 1
+	input <- 2
+	input <- 3
+	close(input)
+	throttled := Throttle(ctx, fake, input, time.Second)
+
+	if value := <-throttled; value != 1 {
+		t.Fatalf("first value %d, want 1", value)
+	}
+	for _, want := range []int{2, 3} {
+		fake.BlockUntil(1)
+		fake.Advance(999 * time.Millisecond)
+		if !nothingWithin(throttled) {
+			t.Fatalf("value %d came less than a second after the one before", want)
+		}
+		fake.Advance(time.Millisecond)
+		if value := <-throttled; value != want {
+			t.Fatalf("got %d, want %d", value, want)
+		}
+	}
+	if !closedWithin(throttled) {
+		t.Error("Throttle didn't close its output when the input closed")
+	}
+}
+
+func TestConcurrencySelectOverTimesOutOnAFakeClock(t *testing.T) {
+	checkNoLeaks(t)
+	fake := clock.NewFake(start)
+	w := newGate()
+	done := make(chan error)
+	go func() {
+		done <- ConcurrencySelectOver(context.Background(), fake, w, manySalutations(10000), manySalutations(10000), time.Hour)
+	}()
+	// hold the loop at its first line while the hour passes
+	<-w.entered
+	fake.Advance(time.Hour)
+	close(w.release)
+	select {
+	case err := <-done:
+		if err != context.DeadlineExceeded {
+			t.Errorf("ConcurrencySelectOver = %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ConcurrencySelectOver didn't time out")
+	}
+}
+
+// recorder is a writer that notices being written to after stop is called
+type recorder struct {
+	mutex   sync.Mutex
+	output  bytes.Buffer
+	stopped bool
+	late    bool
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.late = r.late || r.stopped
+	return r.output.Write(p)
+}
+
+func (r *recorder) stop() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.stopped = true
+}
+
+func TestBasicConcurrencyFinishesBeforeReturning(t *testing.T) {
+	checkNoLeaks(t)
+	for i := 0; i < 50; i++ {
+		var w recorder
+		BasicConcurrencyTo(&w)
+		w.stop()
+		time.Sleep(time.Millisecond)
+		w.mutex.Lock()
+		lines, late := strings.Count(w.output.String(), "\n"), w.late
+		w.mutex.Unlock()
+		if lines != 6 || late {
+			t.Fatalf("run %d: %d lines, written after returning %v:\n%s", i, lines, late, w.output.String())
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package goConcurrency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// BufferedChannel and FixedChannel try to find out when a goroutine has finished by counting values on a channel,
+// and both get it wrong - one can return before "Done!" is printed and the other never returns at all.
+// These types answer "has it finished?" directly:
+//
+//	Group     waits for a set of goroutines and reports the first error any of them returned (like errgroup)
+//	Future    holds the single result of some work, which any number of goroutines can wait for
+//	Barrier   holds goroutines back until all of them have reached the same point, then releases them together
+
+// Group waits for a collection of goroutines. The zero Group is ready to use
+type Group struct {
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+	cancel context.CancelCauseFunc
+}
+
+// GroupWithContext returns a Group and a context that is cancelled as soon as any of its goroutines returns an error,
+// or when Wait returns - so the other goroutines can stop early
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go runs f in a new goroutine
+func (group *Group) Go(f func() error) {
+	group.wg.Add(1)
+	go func() {
+		defer group.wg.Done()
+		if err := f(); err != nil {
+			group.once.Do(func() {
+				group.err = err
+				if group.cancel != nil {
+					group.cancel(err)
+				}
+			})
+		}
+	}()
+}
+
+// Wait blocks until every goroutine started with Go has returned, then returns the first error any of them returned
+func (group *Group) Wait() error {
+	group.wg.Wait()
+	if group.cancel != nil {
+		group.cancel(group.err)
+	}
+	return group.err
+}
+
+// Future is the result of work that finishes once. The first call to Resolve or Reject sets the result,
+// and every goroutine waiting on the future then sees it
+type Future[T any] struct {
+	done  chan struct{}
+	once  sync.Once
+	value T
+	err   error
+}
+
+// NewFuture creates a future with no result yet
+func NewFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// Async runs f in a new goroutine and returns a future for its result
+func Async[T any](f func() (T, error)) *Future[T] {
+	future := NewFuture[T]()
+	go func() {
+		value, err := f()
+		future.complete(value, err)
+	}()
+	return future
+}
+
+// Resolve sets the future's value, reporting false if it already had a result
+func (future *Future[T]) Resolve(value T) bool {
+	return future.complete(value, nil)
+}
+
+// Reject sets the future's error, reporting false if it already had a result
+func (future *Future[T]) Reject(err error) bool {
+	var zero T
+	return future.complete(zero, err)
+}
+
+func (future *Future[T]) complete(value T, err error) (completed bool) {
+	future.once.Do(func() {
+		future.value, future.err = value, err
+		close(future.done)
+		completed = true
+	})
+	return
+}
+
+// Done returns a channel that is closed once the future has a result, for use in a select
+func (future *Future[T]) Done() <-chan struct{} {
+	return future.done
+}
+
+// Wait blocks until the future has a result and returns it, or returns ctx's error if ctx is cancelled first
+func (future *Future[T]) Wait(ctx context.Context) (T, error) {
+	select {
+	case <-future.done:
+		return future.value, future.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Barrier makes a fixed number of goroutines wait for each other. Once the last one arrives they all carry on,
+// and the barrier is ready to be used again
+type Barrier struct {
+	mutex   sync.Mutex
+	parties int
+	waiting int
+	release chan struct{}
+}
+
+// NewBarrier creates a barrier for parties goroutines
+func NewBarrier(parties int) *Barrier {
+	if parties < 1 {
+		panic(fmt.Sprintf("goConcurrency: a barrier needs at least 1 party, not %d", parties))
+	}
+	return &Barrier{parties: parties, release: make(chan struct{})}
+}
+
+// Await blocks until every party has called Await, or returns ctx's error if ctx is cancelled first.
+// A goroutine that gives up is no longer counted, so the others keep waiting for a replacement
+func (barrier *Barrier) Await(ctx context.Context) error {
+	barrier.mutex.Lock()
+	release := barrier.release
+	barrier.waiting++
+	if barrier.waiting == barrier.parties {
+		// the last to arrive releases everyone and resets the barrier for the next round
+		close(release)
+		barrier.release = make(chan struct{})
+		barrier.waiting = 0
+		barrier.mutex.Unlock()
+		return nil
+	}
+	barrier.mutex.Unlock()
+
+	select {
+	case <-release:
+		return nil
+	case <-ctx.Done():
+		barrier.mutex.Lock()
+		defer barrier.mutex.Unlock()
+		select {
+		case <-release:
+			// released while waiting for the lock - too late to give up
+			return nil
+		default:
+		}
+		barrier.waiting--
+		return ctx.Err()
+	}
+}
+
+// BarrierGreeting demonstrates a barrier: every goroutine greets casually, then waits until all of them have,
+// so no formal greeting is printed before the last casual one
+func BarrierGreeting() error {
+	return BarrierGreetingTo(os.Stdout)
+}
+
+// BarrierGreetingTo is BarrierGreeting writing to w
+func BarrierGreetingTo(w io.Writer) error {
+	return BarrierGreetingOver(context.Background(), w, core.VendSalutations())
+}
+
+// BarrierGreetingOver is BarrierGreetingTo with a goroutine for each of salutations
+func BarrierGreetingOver(ctx context.Context, w io.Writer, salutations core.Salutations) error {
+	if len(salutations) == 0 {
+		return nil
+	}
+	w = &lockedWriter{w: w}
+	barrier := NewBarrier(len(salutations))
+	group, ctx := GroupWithContext(ctx)
+	for _, salutation := range salutations {
+		salutation := salutation
+		group.Go(func() error {
+			printGreeting(w, salutation, false)
+			if err := barrier.Await(ctx); err != nil {
+				return err
+			}
+			printGreeting(w, salutation, true)
+			return nil
+		})
+	}
+	return group.Wait()
+}
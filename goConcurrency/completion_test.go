@@ -0,0 +1,194 @@
+package goConcurrency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupWaitsForEveryGoroutine(t *testing.T) {
+	checkNoLeaks(t)
+	var group Group
+	var finished atomic.Int32
+	for i := 0; i < 50; i++ {
+		group.Go(func() error {
+			time.Sleep(time.Millisecond)
+			finished.Add(1)
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if finished.Load() != 50 {
+		t.Errorf("Wait returned with %d of 50 goroutines finished", finished.Load())
+	}
+}
+
+func TestGroupWithContextCancelsOnFirstError(t *testing.T) {
+	checkNoLeaks(t)
+	first := errors.New("first")
+	group, ctx := GroupWithContext(context.Background())
+	group.Go(func() error { return first })
+	for i := 0; i < 10; i++ {
+		group.Go(func() error {
+			<-ctx.Done()
+			return errors.New("stopped because another goroutine failed")
+		})
+	}
+	if err := group.Wait(); err != first {
+		t.Errorf("Wait = %v, want the first error", err)
+	}
+	if !errors.Is(context.Cause(ctx), first) {
+		t.Errorf("ctx was cancelled because of %v, want the first error", context.Cause(ctx))
+	}
+}
+
+func TestFutureFirstResultWins(t *testing.T) {
+	checkNoLeaks(t)
+	future := NewFuture[int]()
+	var wins atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if future.Resolve(i) {
+				wins.Add(1)
+			}
+		}()
+	}
+	results := make([]int, 20)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = future.Wait(context.Background())
+		}()
+	}
+	wg.Wait()
+	if wins.Load() != 1 {
+		t.Fatalf("%d calls to Resolve won, want 1", wins.Load())
+	}
+	for _, result := range results {
+		if result != results[0] {
+			t.Fatalf("waiters saw different results: %v", results)
+		}
+	}
+	if future.Reject(errors.New("too late")) {
+		t.Error("Reject succeeded after Resolve")
+	}
+}
+
+func TestFutureWaitGivesUpWithCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewFuture[string]().Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait = %v, want context.Canceled", err)
+	}
+}
+
+func TestAsync(t *testing.T) {
+	checkNoLeaks(t)
+	failed := errors.New("failed")
+	if value, err := Async(func() (string, error) { return "hi", nil }).Wait(context.Background()); value != "hi" || err != nil {
+		t.Errorf("Async = %q, %v", value, err)
+	}
+	if _, err := Async(func() (string, error) { return "", failed }).Wait(context.Background()); err != failed {
+		t.Errorf("Async = %v, want the error", err)
+	}
+}
+
+func TestBarrierHoldsEveryRound(t *testing.T) {
+	checkNoLeaks(t)
+	const parties, rounds = 5, 20
+	barrier := NewBarrier(parties)
+	var arrived [rounds]atomic.Int32
+	var group Group
+	for p := 0; p < parties; p++ {
+		group.Go(func() error {
+			for round := 0; round < rounds; round++ {
+				arrived[round].Add(1)
+				if err := barrier.Await(context.Background()); err != nil {
+					return err
+				}
+				if n := arrived[round].Load(); n != parties {
+					return errors.New("released before everybody arrived")
+				}
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBarrierAwaitGivesUp(t *testing.T) {
+	checkNoLeaks(t)
+	barrier := NewBarrier(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- barrier.Await(ctx) }()
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Await = %v, want context.Canceled", err)
+	}
+	// the goroutine that gave up isn't counted, so one more arrival isn't enough to release the barrier
+	released := make(chan error)
+	go func() { released <- barrier.Await(context.Background()) }()
+	select {
+	case <-released:
+		t.Fatal("the barrier released after only one of two parties arrived")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if err := barrier.Await(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-released; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBarrierGreetingPrintsEveryCasualGreetingFirst(t *testing.T) {
+	checkNoLeaks(t)
+	salutations := roster(10)
+	for i := range salutations {
+		salutations[i].FormalGreeting = "Good day"
+	}
+	var output bytes.Buffer
+	if err := BarrierGreetingOver(context.Background(), &output, salutations); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("printed %d lines, want 20", len(lines))
+	}
+	for i, line := range lines {
+		if formal := strings.HasPrefix(line, "Good day"); formal != (i >= 10) {
+			t.Fatalf("line %d %q is out of place:\n%s", i, line, output.String())
+		}
+	}
+}
+
+func TestChannelDemosAlwaysFinish(t *testing.T) {
+	checkNoLeaks(t)
+	demos := map[string]func(w *bytes.Buffer){
+		"BufferedChannel": func(w *bytes.Buffer) { BufferedChannelTo(w) },
+		"FixedChannel":    func(w *bytes.Buffer) { FixedChannelTo(w) },
+	}
+	for name, demo := range demos {
+		for i := 0; i < 20; i++ {
+			var output bytes.Buffer
+			demo(&output)
+			if !strings.Contains(output.String(), "Done!\n") {
+				t.Fatalf("%s returned before printing Done!:\n%s", name, output.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package goConcurrency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/registry"
+	"github.com/annicaburns/learngo/store"
+)
+
+// Register the concurrency demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.BasicConcurrency",
+		Description: "start a goroutine and wait for it with a sync.WaitGroup",
+		Run: func(w io.Writer, args registry.Args) error {
+			BasicConcurrencyTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.ChannelConcurrency",
+		Description: "wait for a goroutine to finish with a channel",
+		Params:      []registry.Param{registry.RosterParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			roster, err := store.LoadRoster(args.String("roster"), core.VendSalutations())
+			if err != nil {
+				return err
+			}
+			ChannelConcurrencyOver(w, roster)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.UnBufferedChannel",
+		Description: "an unbuffered channel blocks the second send",
+		Run: func(w io.Writer, args registry.Args) error {
+			UnBufferedChannelTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.BufferedChannel",
+		Description: "a buffered channel plus a future so the final println is always reached",
+		Run: func(w io.Writer, args registry.Args) error {
+			BufferedChannelTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.FixedChannel",
+		Description: "wait for a group of goroutines so the final println is always reached",
+		Run: func(w io.Writer, args registry.Args) error {
+			FixedChannelTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.ChannelWithRange",
+		Description: "range over a channel of salutations until it is closed",
+		Run: func(w io.Writer, args registry.Args) error {
+			ChannelWithRangeTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.ConcurrencySelect",
+		Description: "select between two channels of salutations",
+		Run: func(w io.Writer, args registry.Args) error {
+			ConcurrencySelectTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.GreetingPool",
+		Description: "greet a roster with a bounded pool of workers, in order or as each greeting finishes",
+		Params: []registry.Param{
+			{Name: "workers", Kind: registry.Int, Default: "4", Description: "number of worker goroutines"},
+			{Name: "ordered", Kind: registry.Bool, Default: "true", Description: "print greetings in roster order"},
+			registry.IsFormalParam,
+			registry.RosterParam,
+		},
+		Run: func(w io.Writer, args registry.Args) error {
+			roster, err := store.LoadRoster(args.String("roster"), core.VendSalutations())
+			if err != nil {
+				return err
+			}
+			return GreetingPoolOver(context.Background(), w, roster, args.Int("workers"), args.Bool("ordered"), args.Bool("isFormal"))
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.ConcurrencySelectContext",
+		Description: "select between two channels without spinning, giving up after a timeout",
+		Params: []registry.Param{
+			{Name: "timeout", Kind: registry.String, Default: "1s", Description: "how long to wait for the salutations (0 waits until they are all received)"},
+		},
+		Run: func(w io.Writer, args registry.Args) error {
+			timeout, err := time.ParseDuration(args.String("timeout"))
+			if err != nil {
+				return fmt.Errorf("%w: timeout: %v", registry.ErrInvalidParam, err)
+			}
+			return ConcurrencySelectContextTo(context.Background(), w, timeout)
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.MergeChannels",
+		Description: "merge several channels of salutations, then throttle and batch them",
+		Params: []registry.Param{
+			{Name: "channels", Kind: registry.Int, Default: "3", Description: "number of ChannelGreeter channels to merge"},
+			{Name: "batch", Kind: registry.Int, Default: "4", Description: "salutations per batch"},
+			{Name: "interval", Kind: registry.String, Default: "0", Description: "pass on at most one salutation per interval (0 doesn't throttle)"},
+		},
+		Run: func(w io.Writer, args registry.Args) error {
+			interval, err := time.ParseDuration(args.String("interval"))
+			if err != nil {
+				return fmt.Errorf("%w: interval: %v", registry.ErrInvalidParam, err)
+			}
+			if args.Int("channels") < 1 {
+				return fmt.Errorf("%w: channels must be at least 1, got %d", registry.ErrInvalidParam, args.Int("channels"))
+			}
+			MergeChannelsTo(w, args.Int("channels"), args.Int("batch"), interval)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goConcurrency.BarrierGreeting",
+		Description: "hold goroutines at a barrier so every casual greeting comes before any formal one",
+		Params:      []registry.Param{registry.RosterParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			roster, err := store.LoadRoster(args.String("roster"), core.VendSalutations())
+			if err != nil {
+				return err
+			}
+			return BarrierGreetingOver(context.Background(), w, roster)
+		},
+	})
+}
@@ -1,9 +1,15 @@
 package goConcurrency
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/annicaburns/learngo/clock"
+	"github.com/annicaburns/learngo/core"
 	"github.com/annicaburns/learngo/goInterfaces"
 )
 
@@ -13,28 +19,51 @@ import (
 // Goroutine is a lightweight thread managed by the GO runtime. Use keyword "go" to execute a "routine" concurrently. run this and keep going, we don't want to block anything waiting fot this to finish
 // https://golang.org/doc/effective_go.html#goroutines
 
+// Every demo has a "To" variant that writes to any io.Writer, and the original simply calls it with os.Stdout
+// Most io.Writers (a bytes.Buffer for example) aren't safe to write to from more than one goroutine,
+// so each "To" variant wraps w in a lockedWriter before handing it to its goroutines
+
+// lockedWriter serializes writes to an io.Writer shared by several goroutines
+type lockedWriter struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+func (lw *lockedWriter) Write(p []byte) (n int, err error) {
+	lw.mutex.Lock()
+	defer lw.mutex.Unlock()
+	return lw.w.Write(p)
+}
+
 // BasicConcurrency demonstrates Go's built in concurrency handling
 func BasicConcurrency() {
-	go iterateAndPrint(3, true)
-	iterateAndPrint(3, false)
-	// If we don't add in a "wait" period, the BasicConcurrency function will exit before the  the asyncronous call (go iterateAndPrint)
+	BasicConcurrencyTo(os.Stdout)
+}
+
+// BasicConcurrencyTo is BasicConcurrency writing to w
+func BasicConcurrencyTo(w io.Writer) {
+	w = &lockedWriter{w: w}
+	// If we don't wait, the BasicConcurrency function will exit before the asyncronous call (go iterateAndPrint)
 	// has time to spin up and finish.
-	// We have to keep this method alive for long enough to finish
-	time.Sleep(100 * time.Millisecond)
+	// Sleeping "long enough" is a guess that is sometimes wrong, so a WaitGroup counts the goroutine in and out instead
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		iterateAndPrint(w, core.VendSalutations(), true)
+	}()
+	iterateAndPrint(w, core.VendSalutations(), false)
+	// Wait blocks until the goroutine has called Done, so nothing is written to w after this function returns
+	wg.Wait()
 }
 
-func printGreeting(salutation goInterfaces.Salutation, isFormal bool) {
-	var greeting = salutation.CasualGreeting
-	if formalGreeting := salutation.FormalGreeting; isFormal {
-		greeting = formalGreeting
-	}
-	fmt.Println(greeting+", ", salutation.Name)
+func printGreeting(w io.Writer, salutation core.Salutation, isFormal bool) {
+	fmt.Fprintln(w, salutation.Greeting(isFormal)+", ", salutation.Name)
 }
 
-func iterateAndPrint(times int, isFormal bool) {
-	var salutations = goInterfaces.VendSalutations()
-	for i := 0; i < times; i++ {
-		printGreeting(salutations[i], isFormal)
+func iterateAndPrint(w io.Writer, salutations core.Salutations, isFormal bool) {
+	for i := 0; i < len(salutations); i++ {
+		printGreeting(w, salutations[i], isFormal)
 	}
 }
 
@@ -45,15 +74,26 @@ func iterateAndPrint(times int, isFormal bool) {
 // Also... if a channel is being read in a for loop, that loop will not exit until the channel is closed by
 // whatever is feeding it
 func ChannelConcurrency() {
+	ChannelConcurrencyTo(os.Stdout)
+}
+
+// ChannelConcurrencyTo is ChannelConcurrency writing to w
+func ChannelConcurrencyTo(w io.Writer) {
+	ChannelConcurrencyOver(w, core.VendSalutations())
+}
+
+// ChannelConcurrencyOver is ChannelConcurrency greeting any roster of salutations
+func ChannelConcurrencyOver(w io.Writer, salutations core.Salutations) {
+	w = &lockedWriter{w: w}
 	// create the channel
 	done := make(chan bool)
 	// create and execute an anonymous function to augment iterateAndPrint with the ability to communicate over a channel
 	// this anonymous function is also a closure and can access the value of the done variable
 	go func() {
-		iterateAndPrint(3, true)
+		iterateAndPrint(w, salutations, true)
 		done <- true
 	}()
-	iterateAndPrint(3, false)
+	iterateAndPrint(w, salutations, false)
 	// we could create a variable to read the value out of done, but it's not necessary
 	// because this line will block until we can read a value out of done, which won't happen until we write to done
 	<-done
@@ -63,18 +103,24 @@ func ChannelConcurrency() {
 // Unbuffered channels are serial - channel processes can only be run one at a time
 // Buffered channels process as many routines as they can before they block
 func UnBufferedChannel() {
+	UnBufferedChannelTo(os.Stdout)
+}
+
+// UnBufferedChannelTo is UnBufferedChannel writing to w
+func UnBufferedChannelTo(w io.Writer) {
+	w = &lockedWriter{w: w}
 	// create the channel
 	done := make(chan bool)
 	go func() {
-		iterateAndPrint(3, true)
+		iterateAndPrint(w, core.VendSalutations(), true)
 		done <- true
 		// This second true will never be allowed to get onto the channel because it's unbuffered. This will block
 		// indefinitely, but as soon as the first done moves onto the channel, the function will exit and the println
 		// will never be reached
 		done <- true
-		println("Done!")
+		fmt.Fprintln(w, "Done!")
 	}()
-	iterateAndPrint(3, false)
+	iterateAndPrint(w, core.VendSalutations(), false)
 	// we could create a variable to read the value out of done, but it's not necessary
 	// because this line will block until we can read a value out of done, which won't happen until we write to done
 	<-done
@@ -85,53 +131,63 @@ func UnBufferedChannel() {
 // The value of the buffer size indicates how many items can be written onto the channel before the channel has to be read
 // a buffer size of one means the channel will get read after the first item is written onto the channel
 // a buffer size of two means the channel won't get read until after the second item is written onto the channel
-// But this code actually creates a race condition because SOMETIMES the println won't be reached before the function exists
+// But reading from the channel only tells us the first item was written, so SOMETIMES the println wouldn't be reached
+// before the function exits - a Future that the goroutine resolves once it really has finished removes that race
 func BufferedChannel() {
+	BufferedChannelTo(os.Stdout)
+}
+
+// BufferedChannelTo is BufferedChannel writing to w
+func BufferedChannelTo(w io.Writer) {
+	w = &lockedWriter{w: w}
 	// create the channel
 	done := make(chan bool, 2)
+	finished := NewFuture[struct{}]()
 	go func() {
-		iterateAndPrint(3, true)
+		iterateAndPrint(w, core.VendSalutations(), true)
 		done <- true
-		// This second true will never be allowed to get onto the channel because it's unbuffered. This will block
-		// indefinitely, but as soon as the first done moves onto the channel, the function will exit and the println
-		// will never be reached
+		// There is room on the channel for this second true, so it doesn't block and the println is reached
 		done <- true
-		println("Done!")
+		fmt.Fprintln(w, "Done!")
+		finished.Resolve(struct{}{})
 	}()
-	iterateAndPrint(3, false)
-	// we could create a variable to read the value out of done, but it's not necessary
-	// because this line will block until we can read a value out of done, which won't happen until we write to done
+	iterateAndPrint(w, core.VendSalutations(), false)
+	// this line blocks until we can read a value out of done - but the goroutine may not have reached the println yet
 	<-done
+	// so also wait for the goroutine to say it has finished
+	finished.Wait(context.Background())
 }
 
 // FixedChannel demonstrates code that solves the race condition that exists in the BufferedChannel function
 // race condition: SOMETIMES the println won't be reached before the function exists
-//
+// It used to block forever in an infinite loop so the println was always reached - which obviously isn't a good solution.
+// A Group waits for the goroutine itself rather than for values it writes onto a channel,
+// so Wait returns as soon as the goroutine (println and all) has finished
 func FixedChannel() {
-	// create the channel
-	done := make(chan bool, 2)
-	go func() {
-		iterateAndPrint(3, true)
-		done <- true
-		// Introducing a sleep here demonstrates that the race condition exists
-		time.Sleep(100 * time.Millisecond)
-		done <- true
-		println("Done!")
-	}()
-	iterateAndPrint(3, false)
-	// we could create a variable to read the value out of done, but it's not necessary
-	// because this line will block until we can read a value out of done, which won't happen until we write to done
-	<-done
-	// The infinite loop below will block and the function will never exit on it's own,
-	// which means the println above will always be reached - but this obviously isn't a good solution.
-	// Instead we would need to read a value and react by manually exiting the function
-	for {
-		time.Sleep(100 * time.Millisecond)
-	}
+	FixedChannelTo(os.Stdout)
+}
+
+// FixedChannelTo is FixedChannel writing to w
+func FixedChannelTo(w io.Writer) {
+	w = &lockedWriter{w: w}
+	var group Group
+	group.Go(func() error {
+		iterateAndPrint(w, core.VendSalutations(), true)
+		// Introducing a sleep here used to demonstrate that the race condition exists - Wait doesn't care how long it takes
+		fmt.Fprintln(w, "Done!")
+		return nil
+	})
+	iterateAndPrint(w, core.VendSalutations(), false)
+	group.Wait()
 }
 
 // ChannelWithRange demonstrates
 func ChannelWithRange() {
+	ChannelWithRangeTo(os.Stdout)
+}
+
+// ChannelWithRangeTo is ChannelWithRange writing to w
+func ChannelWithRangeTo(w io.Writer) {
 	var salutations = goInterfaces.VendSalutations()
 	// create a channel that will hold Salutations
 	salChannel := make(chan goInterfaces.Salutation)
@@ -140,7 +196,7 @@ func ChannelWithRange() {
 	// Eventually, when all values have been fed into the channel, the channel will be closed by ChannelGreeter.
 	go salutations.ChannelGreeter(salChannel)
 	for salutation := range salChannel {
-		fmt.Println(salutation.Name)
+		fmt.Fprintln(w, salutation.Name)
 		// This loop will run as long as the channel is open and pull values out of the channel (by reading and printing)
 		// them until it receives a "channel closed" message after the last salutation.
 		// This loop will then exit and the function will exit.
@@ -153,6 +209,11 @@ func ChannelWithRange() {
 // if more than one is "ready", execute one at random
 // if none are ready, block unless a default is defined
 func ConcurrencySelect() {
+	ConcurrencySelectTo(os.Stdout)
+}
+
+// ConcurrencySelectTo is ConcurrencySelect writing to w
+func ConcurrencySelectTo(w io.Writer) {
 	var salutations = goInterfaces.VendSalutations()
 	salChannel1 := make(chan goInterfaces.Salutation)
 	salChannel2 := make(chan goInterfaces.Salutation)
@@ -162,18 +223,81 @@ func ConcurrencySelect() {
 		select {
 		case salutation, ok := <-salChannel1:
 			if ok {
-				fmt.Println(salutation.Name, ":1")
+				fmt.Fprintln(w, salutation.Name, ":1")
 			} else {
 				return
 			}
 		case salutation, ok := <-salChannel2:
 			if ok {
-				fmt.Println(salutation.Name, ":2")
+				fmt.Fprintln(w, salutation.Name, ":2")
 			} else {
 				return
 			}
 		default:
-			fmt.Println("waiting")
+			fmt.Fprintln(w, "waiting")
+		}
+	}
+}
+
+// ConcurrencySelectContext demonstrates a select loop that waits without spinning.
+// ConcurrencySelect's default case runs every time neither channel is ready, so it prints "waiting" as fast as it can.
+// Leaving the default out makes select block until a channel is ready, and a case on ctx.Done lets the loop give up.
+// A closed channel is set to nil, because receiving from a nil channel blocks forever, so select stops choosing that case.
+// The loop ends when both channels are closed, when ctx is cancelled or when timeout (if it isn't 0) runs out on clk.
+// Before returning it cancels the producers and waits for them to close their channels, so no goroutine is left behind
+func ConcurrencySelectContext(ctx context.Context, timeout time.Duration) error {
+	return ConcurrencySelectContextTo(ctx, os.Stdout, timeout)
+}
+
+// ConcurrencySelectContextTo is ConcurrencySelectContext writing to w
+func ConcurrencySelectContextTo(ctx context.Context, w io.Writer, timeout time.Duration) error {
+	return ConcurrencySelectOver(ctx, clock.Real, w, goInterfaces.VendSalutations(), goInterfaces.VendSalutations(), timeout)
+}
+
+// ConcurrencySelectOver is ConcurrencySelectContextTo selecting between the two rosters first and second,
+// timing out on clk - clock.Real if nil
+func ConcurrencySelectOver(ctx context.Context, clk clock.Clock, w io.Writer, first, second goInterfaces.Salutations, timeout time.Duration) (err error) {
+	if clk == nil {
+		clk = clock.Real
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	// a nil channel is never ready, so without a timeout the deadline case is never chosen
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := clk.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C()
+	}
+	producer1, producer2 := first.Channel(ctx), second.Channel(ctx)
+	defer func() {
+		cancel()
+		// a producer closes its channel as soon as it sees the cancellation, ending these loops
+		for range producer1 {
+		}
+		for range producer2 {
+		}
+	}()
+	salChannel1, salChannel2 := producer1, producer2
+	for salChannel1 != nil || salChannel2 != nil {
+		select {
+		case salutation, ok := <-salChannel1:
+			if !ok {
+				salChannel1 = nil
+				continue
+			}
+			fmt.Fprintln(w, salutation.Name, ":1")
+		case salutation, ok := <-salChannel2:
+			if !ok {
+				salChannel2 = nil
+				continue
+			}
+			fmt.Fprintln(w, salutation.Name, ":2")
+		case <-deadline:
+			return context.DeadlineExceeded
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
+	// cancelling ctx also makes the producers close their channels, so the loop can end that way too
+	return ctx.Err()
 }
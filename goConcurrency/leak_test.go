@@ -0,0 +1,40 @@
+package goConcurrency
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// checkNoLeaks fails the test if more goroutines are running when it ends than when it started.
+// A goroutine that has just been told to stop may not have been scheduled yet, so they get a moment to finish
+func checkNoLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if after := runtime.NumGoroutine(); after > before {
+			stacks := make([]byte, 1<<16)
+			stacks = stacks[:runtime.Stack(stacks, true)]
+			t.Errorf("%d goroutines leaked:\n%s", after-before, stacks)
+		}
+	})
+}
+
+// closedWithin reports whether channel is closed within a second, throwing away anything still sent on it
+func closedWithin[T any](channel <-chan T) bool {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-channel:
+			if !ok {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package goConcurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// Pool renders greetings for a stream of salutations with a fixed number of worker goroutines.
+// Nothing sleeps and waits for "long enough": every goroutine is tracked by a sync.WaitGroup or a closed channel,
+// and a context.Context tells them all to stop - when the caller cancels, or when any greeting fails.
+//
+//	feeder      reads the input channel, numbers each salutation and hands it to the workers
+//	workers     render greetings concurrently - there are never more than Workers of them
+//	collector   runs on the caller's goroutine and passes each greeting to emit, in input order if Ordered is set
+//
+// In Ordered mode a greeting that finishes early waits for the ones before it. The feeder only lets
+// 2 x Workers salutations be in flight at once, so the waiting greetings can't pile up without limit.
+
+// ErrNoName is returned by the default renderer for a salutation without a name
+var ErrNoName = errors.New("goConcurrency: salutation has no name")
+
+// Greeting is one rendered greeting
+type Greeting struct {
+	Index      int // position of the salutation in the input stream
+	Salutation core.Salutation
+	Message    string
+}
+
+// RenderFunc renders the greeting for a single salutation. It should give up when ctx is done
+type RenderFunc func(ctx context.Context, salutation core.Salutation) (string, error)
+
+// MessageRenderer renders core.Salutation.Message, failing with ErrNoName for a salutation without a name
+func MessageRenderer(isFormal bool) RenderFunc {
+	return func(ctx context.Context, salutation core.Salutation) (string, error) {
+		if salutation.Name == "" {
+			return "", ErrNoName
+		}
+		return salutation.Message(isFormal), nil
+	}
+}
+
+// Pool describes how to run the pipeline. The zero Pool uses one worker, casual messages and any order
+type Pool struct {
+	Workers int
+	Ordered bool
+	Render  RenderFunc
+}
+
+type job struct {
+	index      int
+	salutation core.Salutation
+}
+
+// Run greets every salutation from input until input is closed, passing each greeting to emit.
+// emit is always called from the goroutine that called Run, so it doesn't need any locking.
+// Run returns the first error from a render or from emit, or ctx's error if it is cancelled first.
+// Every goroutine Run started has finished by the time it returns
+func (pool Pool) Run(ctx context.Context, input <-chan core.Salutation, emit func(Greeting) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	workers := pool.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	render := pool.Render
+	if render == nil {
+		render = MessageRenderer(false)
+	}
+	// window holds a token for every salutation in flight when the output has to stay in order
+	var window chan struct{}
+	if pool.Ordered {
+		window = make(chan struct{}, 2*workers)
+	}
+
+	jobs := make(chan job)
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			var salutation core.Salutation
+			select {
+			case <-ctx.Done():
+				return
+			case s, ok := <-input:
+				if !ok {
+					return
+				}
+				salutation = s
+			}
+			if window != nil {
+				select {
+				case window <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case jobs <- job{index, salutation}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan Greeting)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				message, err := render(ctx, j.salutation)
+				if err != nil {
+					cancel(fmt.Errorf("greeting salutation %d (%q): %w", j.index, j.salutation.Name, err))
+					return
+				}
+				select {
+				case results <- Greeting{Index: j.index, Salutation: j.salutation, Message: message}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]Greeting)
+	next := 0
+	for greeting := range results {
+		if ctx.Err() != nil {
+			// keep draining so the workers can finish, but stop emitting
+			continue
+		}
+		if !pool.Ordered {
+			if err := emit(greeting); err != nil {
+				cancel(err)
+			}
+			continue
+		}
+		pending[greeting.Index] = greeting
+		for ready, exists := pending[next]; exists && ctx.Err() == nil; ready, exists = pending[next] {
+			delete(pending, next)
+			next++
+			<-window
+			if err := emit(ready); err != nil {
+				cancel(err)
+			}
+		}
+	}
+	return context.Cause(ctx)
+}
+
+// Collect runs the pool over salutations and returns every greeting, in input order when the pool is Ordered
+func (pool Pool) Collect(ctx context.Context, salutations core.Salutations) (greetings []Greeting, err error) {
+	input := make(chan core.Salutation)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		defer close(input)
+		for _, salutation := range salutations {
+			select {
+			case input <- salutation:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	err = pool.Run(ctx, input, func(greeting Greeting) error {
+		greetings = append(greetings, greeting)
+		return nil
+	})
+	return
+}
+
+// GreetingPool demonstrates greeting the built in salutations with a pool of workers
+func GreetingPool(workers int, ordered, isFormal bool) error {
+	return GreetingPoolTo(os.Stdout, workers, ordered, isFormal)
+}
+
+// GreetingPoolTo is GreetingPool writing to w
+func GreetingPoolTo(w io.Writer, workers int, ordered, isFormal bool) error {
+	return GreetingPoolOver(context.Background(), w, core.VendSalutations(), workers, ordered, isFormal)
+}
+
+// GreetingPoolOver is GreetingPoolTo greeting salutations, stopping early if ctx is cancelled
+func GreetingPoolOver(ctx context.Context, w io.Writer, salutations core.Salutations, workers int, ordered, isFormal bool) error {
+	pool := Pool{Workers: workers, Ordered: ordered, Render: MessageRenderer(isFormal)}
+	greetings, err := pool.Collect(ctx, salutations)
+	for _, greeting := range greetings {
+		fmt.Fprintf(w, "%d: %s\n", greeting.Index, greeting.Message)
+	}
+	return err
+}
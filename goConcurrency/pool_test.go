@@ -0,0 +1,180 @@
+package goConcurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+func roster(n int) core.Salutations {
+	salutations := make(core.Salutations, n)
+	for i := range salutations {
+		salutations[i] = core.Salutation{Name: fmt.Sprintf("Person %d", i), CasualGreeting: "Hi"}
+	}
+	return salutations
+}
+
+func TestPoolOrdered(t *testing.T) {
+	checkNoLeaks(t)
+	// later salutations finish first, so the collector really has to put them back in order
+	render := func(ctx context.Context, salutation core.Salutation) (string, error) {
+		var index int
+		fmt.Sscanf(salutation.Name, "Person %d", &index)
+		time.Sleep(time.Duration(20-index) * time.Millisecond)
+		return salutation.Name, nil
+	}
+	greetings, err := Pool{Workers: 4, Ordered: true, Render: render}.Collect(context.Background(), roster(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(greetings) != 20 {
+		t.Fatalf("got %d greetings, want 20", len(greetings))
+	}
+	for i, greeting := range greetings {
+		if greeting.Index != i {
+			t.Fatalf("greeting %d has index %d - not in order", i, greeting.Index)
+		}
+	}
+}
+
+func TestPoolUnorderedGreetsEverybody(t *testing.T) {
+	checkNoLeaks(t)
+	greetings, err := Pool{Workers: 3}.Collect(context.Background(), roster(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int]bool)
+	for _, greeting := range greetings {
+		seen[greeting.Index] = true
+	}
+	if len(greetings) != 50 || len(seen) != 50 {
+		t.Errorf("got %d greetings for %d salutations, want 50 of each", len(greetings), len(seen))
+	}
+}
+
+func TestPoolNeverRunsMoreThanWorkers(t *testing.T) {
+	checkNoLeaks(t)
+	var running, most atomic.Int32
+	render := func(ctx context.Context, salutation core.Salutation) (string, error) {
+		now := running.Add(1)
+		for {
+			previous := most.Load()
+			if now <= previous || most.CompareAndSwap(previous, now) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return "", nil
+	}
+	if _, err := (Pool{Workers: 3, Render: render}).Collect(context.Background(), roster(30)); err != nil {
+		t.Fatal(err)
+	}
+	if most.Load() > 3 {
+		t.Errorf("%d renders ran at once with 3 workers", most.Load())
+	}
+}
+
+func TestPoolRenderErrorStopsEverything(t *testing.T) {
+	checkNoLeaks(t)
+	salutations := roster(100)
+	salutations[10].Name = ""
+	for _, ordered := range []bool{false, true} {
+		_, err := Pool{Workers: 4, Ordered: ordered}.Collect(context.Background(), salutations)
+		if !errors.Is(err, ErrNoName) {
+			t.Errorf("ordered %v: Collect = %v, want ErrNoName", ordered, err)
+		}
+	}
+}
+
+func TestPoolEmitErrorStopsEverything(t *testing.T) {
+	checkNoLeaks(t)
+	stop := errors.New("that's enough")
+	input := make(chan core.Salutation)
+	stopFeeding := make(chan struct{})
+	fed := make(chan struct{})
+	go func() {
+		defer close(fed)
+		// input is never closed - Run must stop because emit failed, not because the salutations ran out
+		for _, salutation := range roster(1000) {
+			select {
+			case input <- salutation:
+			case <-stopFeeding:
+				return
+			}
+		}
+	}()
+	defer func() {
+		close(stopFeeding)
+		<-fed
+	}()
+	emitted := 0
+	err := Pool{Workers: 4, Ordered: true}.Run(context.Background(), input, func(Greeting) error {
+		if emitted++; emitted == 5 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Run = %v, want the emit error", err)
+	}
+	if emitted != 5 {
+		t.Errorf("emit was called %d times, want it to stop at 5", emitted)
+	}
+}
+
+func TestPoolCancellation(t *testing.T) {
+	checkNoLeaks(t)
+	for _, ordered := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		input := make(chan core.Salutation)
+		started := make(chan struct{}, 1)
+		render := func(ctx context.Context, salutation core.Salutation) (string, error) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		done := make(chan error)
+		go func() {
+			done <- Pool{Workers: 4, Ordered: ordered, Render: render}.Run(ctx, input, func(Greeting) error { return nil })
+		}()
+		go func() { input <- core.Salutation{Name: "Annica"} }()
+		<-started
+		cancel()
+		select {
+		case err := <-done:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("ordered %v: Run = %v, want context.Canceled", ordered, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("ordered %v: Run didn't return after ctx was cancelled", ordered)
+		}
+	}
+}
+
+func TestPoolCancelledWhileInputIsIdle(t *testing.T) {
+	checkNoLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		// nothing is ever sent and input is never closed
+		done <- Pool{Workers: 2}.Run(ctx, make(chan core.Salutation), func(Greeting) error { return nil })
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run didn't return after ctx was cancelled")
+	}
+}
@@ -0,0 +1,74 @@
+package goConcurrency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/goInterfaces"
+)
+
+func manySalutations(n int) goInterfaces.Salutations {
+	salutations := make(goInterfaces.Salutations, n)
+	for i := range salutations {
+		salutations[i] = goInterfaces.Salutation{Name: fmt.Sprintf("Person %d", i)}
+	}
+	return salutations
+}
+
+func TestConcurrencySelectOverReceivesBothRosters(t *testing.T) {
+	checkNoLeaks(t)
+	var output bytes.Buffer
+	if err := ConcurrencySelectOver(context.Background(), nil, &output, manySalutations(5), manySalutations(7), 0); err != nil {
+		t.Fatal(err)
+	}
+	// unlike ConcurrencySelect it doesn't stop at the first closed channel, and never prints "waiting"
+	if lines := strings.Count(output.String(), "\n"); lines != 12 {
+		t.Errorf("printed %d lines, want 12:\n%s", lines, output.String())
+	}
+	if strings.Contains(output.String(), "waiting") {
+		t.Error("the select loop spun")
+	}
+}
+
+// gate is a writer whose first Write waits until release is closed
+type gate struct {
+	entered, release chan struct{}
+	first            bool
+}
+
+func newGate() *gate {
+	return &gate{entered: make(chan struct{}), release: make(chan struct{}), first: true}
+}
+
+func (g *gate) Write(p []byte) (int, error) {
+	if g.first {
+		g.first = false
+		close(g.entered)
+		<-g.release
+	}
+	return len(p), nil
+}
+
+func TestConcurrencySelectOverStopsWhenCancelled(t *testing.T) {
+	checkNoLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := newGate()
+	done := make(chan error)
+	go func() { done <- ConcurrencySelectOver(ctx, nil, w, manySalutations(10000), manySalutations(10000), 0) }()
+	<-w.entered
+	cancel()
+	close(w.release)
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ConcurrencySelectOver = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ConcurrencySelectOver didn't return after ctx was cancelled")
+	}
+}
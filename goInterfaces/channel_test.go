@@ -0,0 +1,71 @@
+package goInterfaces
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// checkNoLeaks fails the test if more goroutines are running when it ends than when it started
+func checkNoLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if after := runtime.NumGoroutine(); after > before {
+			t.Errorf("%d goroutines leaked", after-before)
+		}
+	})
+}
+
+func TestChannelGreeterContextSendsEverything(t *testing.T) {
+	checkNoLeaks(t)
+	var names []string
+	for salutation := range VendSalutations().Channel(context.Background()) {
+		names = append(names, salutation.Name)
+	}
+	if len(names) != 3 || names[0] != "Annica" || names[2] != "Marisol" {
+		t.Errorf("received %v, want every salutation in order", names)
+	}
+}
+
+func TestChannelGreeterContextStopsWhenCancelled(t *testing.T) {
+	checkNoLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	channel := make(chan Salutation)
+	done := make(chan error)
+	go func() { done <- VendSalutations().ChannelGreeterContext(ctx, channel) }()
+
+	// read one salutation, then stop reading - the producer is now blocked sending the second
+	<-channel
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ChannelGreeterContext = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the producer is still blocked a second after ctx was cancelled")
+	}
+	if _, ok := <-channel; ok {
+		t.Error("the channel is still open after the producer returned")
+	}
+}
+
+func TestChannelGreeterContextAlreadyCancelled(t *testing.T) {
+	checkNoLeaks(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	channel := make(chan Salutation)
+	if err := VendSalutations().ChannelGreeterContext(ctx, channel); !errors.Is(err, context.Canceled) {
+		t.Errorf("ChannelGreeterContext = %v, want context.Canceled", err)
+	}
+	if _, ok := <-channel; ok {
+		t.Error("the channel wasn't closed")
+	}
+}
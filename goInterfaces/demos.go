@@ -0,0 +1,49 @@
+package goInterfaces
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/annicaburns/learngo/registry"
+)
+
+// Register the interface demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "goInterfaces.PrintGreetings",
+		Description: "call methods on a named slice type",
+		Run: func(w io.Writer, args registry.Args) error {
+			PrintGreetingsTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goInterfaces.PrintRenamable",
+		Description: "pass a pointer to a function that takes an interface",
+		Run: func(w io.Writer, args registry.Args) error {
+			PrintRenamableTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goInterfaces.PrintWriterType",
+		Description: "use a Salutation as an io.Writer",
+		Run: func(w io.Writer, args registry.Args) error {
+			PrintWriterTypeTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goInterfaces.QuerySalutations",
+		Description: "select salutations by content with a query",
+		Params:      []registry.Param{{Name: "query", Kind: registry.String, Default: `name ~ "^Ma" sort by name desc`, Description: "query such as casual = Hey or name ~ ^A"}},
+		Run: func(w io.Writer, args registry.Args) error {
+			salutations, err := VendSalutations().Query(args.String("query"))
+			if err != nil {
+				return fmt.Errorf("%w: %v", registry.ErrInvalidParam, err)
+			}
+			salutations.greetTo(w, false)
+			return nil
+		},
+	})
+}
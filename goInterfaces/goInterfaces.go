@@ -1,7 +1,10 @@
 package goInterfaces
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 )
 
 // https://golang.org/doc/effective_go.html#methods
@@ -17,10 +20,11 @@ import (
 // See goSwitch.SwitchType for an example
 
 // Salutation is a single object
+// The struct tags tell encoding/json and encoding/xml what to call each field
 type Salutation struct {
-	Name           string
-	CasualGreeting string
-	FormalGreeting string
+	Name           string `json:"name" xml:"name,attr"`
+	CasualGreeting string `json:"casualGreeting" xml:"casualGreeting"`
+	FormalGreeting string `json:"formalGreeting" xml:"formalGreeting"`
 }
 
 type renamable interface {
@@ -31,6 +35,11 @@ func (salutation *Salutation) rename(newName string) {
 	salutation.Name = newName
 }
 
+// Rename is rename for code outside goInterfaces, which can't see the unexported method or the renamable interface
+func (salutation *Salutation) Rename(newName string) {
+	salutation.rename(newName)
+}
+
 // Salutations is a named type representing a slice of Salutations
 type Salutations []Salutation
 
@@ -42,6 +51,29 @@ func (salutations Salutations) ChannelGreeter(channel chan Salutation) {
 	close(channel)
 }
 
+// ChannelGreeterContext is ChannelGreeter for a consumer that might stop reading.
+// A plain send blocks forever once nobody is receiving, leaking the goroutine that runs ChannelGreeter.
+// Here every send also waits on ctx.Done, so cancelling ctx stops the producer - and the channel is closed either way.
+// It returns ctx's error if it was cancelled before every salutation was sent
+func (salutations Salutations) ChannelGreeterContext(ctx context.Context, channel chan<- Salutation) error {
+	defer close(channel)
+	for _, s := range salutations {
+		select {
+		case channel <- s:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Channel starts a ChannelGreeterContext producer and returns the channel it fills
+func (salutations Salutations) Channel(ctx context.Context) <-chan Salutation {
+	channel := make(chan Salutation)
+	go salutations.ChannelGreeterContext(ctx, channel)
+	return channel
+}
+
 // VendSalutations can be used program wide to produce a starter slice of Salutations
 func VendSalutations() (salutations Salutations) {
 	salutations = Salutations{
@@ -54,20 +86,30 @@ func VendSalutations() (salutations Salutations) {
 
 // this greet function is a method that operates on our named type - Salutations
 func (salutations Salutations) greet(isFormal bool) {
+	salutations.greetTo(os.Stdout, isFormal)
+}
+
+// greetTo is greet writing to w
+func (salutations Salutations) greetTo(w io.Writer, isFormal bool) {
 	for _, s := range salutations {
 		var greeting = s.CasualGreeting
 		if formalGreeting := s.FormalGreeting; isFormal {
 			greeting = formalGreeting
 		}
-		fmt.Println(greeting + ", " + s.Name)
+		fmt.Fprintln(w, greeting+", "+s.Name)
 	}
 }
 
 // PrintGreetings is used to demonstrate calling a method
 func PrintGreetings() {
+	PrintGreetingsTo(os.Stdout)
+}
+
+// PrintGreetingsTo is PrintGreetings writing to w
+func PrintGreetingsTo(w io.Writer) {
 	var salutations = VendSalutations()
 	salutations[0].rename("Jessica")
-	salutations.greet(false)
+	salutations.greetTo(w, false)
 }
 
 func renameToFrog(r renamable) {
@@ -76,9 +118,14 @@ func renameToFrog(r renamable) {
 
 // PrintRenamable is used to demonstrate calling a method that takes an interface parameter
 func PrintRenamable() {
+	PrintRenamableTo(os.Stdout)
+}
+
+// PrintRenamableTo is PrintRenamable writing to w
+func PrintRenamableTo(w io.Writer) {
 	var salutations = VendSalutations()
 	renameToFrog(&salutations[0])
-	salutations.greet(false)
+	salutations.greetTo(w, false)
 }
 
 // Implementing the GO Writer interface
@@ -92,10 +139,15 @@ func (salutation *Salutation) Write(p []byte) (n int, err error) {
 
 // PrintWriterType is used to demonstrate calling a method on a type that implements an interface
 func PrintWriterType() {
+	PrintWriterTypeTo(os.Stdout)
+}
+
+// PrintWriterTypeTo is PrintWriterType writing to w
+func PrintWriterTypeTo(w io.Writer) {
 	var salutations = VendSalutations()
 
 	fmt.Fprintf(&salutations[0], "%d New Name", 1)
-	fmt.Println(salutations[0])
-	fmt.Println(salutations[1])
+	fmt.Fprintln(w, salutations[0])
+	fmt.Fprintln(w, salutations[1])
 
 }
@@ -0,0 +1,427 @@
+package goInterfaces
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Salutations can be selected by what they hold, not just by position, with a small query language:
+//
+//	name ~ "^Ma" and formal = "Hello"
+//	not (casual = Hey or casual = Howdy) sort by name desc limit 10 offset 20
+//
+// A condition compares a field - name, casual or formal (also casualGreeting and formalGreeting) - with a value:
+//   =   equal            !=  not equal
+//   ~   matches a regexp !~  doesn't match a regexp - use (?i) in the pattern to ignore case
+// A value is a word, or in double quotes with Go escapes, or in single quotes.
+// Conditions combine with and, or, not and parentheses - and binds tighter than or.
+// After the conditions come optional clauses in this order: "sort by" one or more fields separated by commas,
+// each followed by asc or desc, then "limit n" and "offset n". Keywords and field names ignore case.
+// The empty query selects everything.
+
+// ErrInvalidQuery is wrapped by every QueryError. Use errors.Is to check for it
+var ErrInvalidQuery = errors.New("goInterfaces: invalid query")
+
+// QueryError reports where a query stopped making sense
+type QueryError struct {
+	Position int // byte offset in the query, counting from 0
+	Message  string
+}
+
+func (err *QueryError) Error() string {
+	return fmt.Sprintf("goInterfaces: invalid query at position %d: %s", err.Position, err.Message)
+}
+
+func (err *QueryError) Unwrap() error {
+	return ErrInvalidQuery
+}
+
+// queryFields are the fields a query can name
+var queryFields = map[string]func(Salutation) string{
+	"name":           func(s Salutation) string { return s.Name },
+	"casual":         func(s Salutation) string { return s.CasualGreeting },
+	"casualgreeting": func(s Salutation) string { return s.CasualGreeting },
+	"formal":         func(s Salutation) string { return s.FormalGreeting },
+	"formalgreeting": func(s Salutation) string { return s.FormalGreeting },
+}
+
+// SortKey is one field a Query sorts by
+type SortKey struct {
+	Field      string // name, casual or formal
+	Descending bool
+}
+
+// Query is a parsed query. Use ParseQuery to create one
+type Query struct {
+	Where  func(Salutation) bool // nil selects everything
+	Sort   []SortKey
+	Limit  int // zero means no limit
+	Offset int
+	text   string
+}
+
+// ParseQuery parses a query. A mistake is reported as a *QueryError
+func ParseQuery(text string) (*Query, error) {
+	tokens, err := lexQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	parser := &queryParser{tokens: tokens}
+	query := &Query{text: text}
+	if !parser.atKeyword("sort", "limit", "offset") && parser.peek().kind != tokenEnd {
+		if query.Where, err = parser.or(); err != nil {
+			return nil, err
+		}
+	}
+	if parser.atKeyword("sort") {
+		parser.next()
+		if err := parser.expectKeyword("by"); err != nil {
+			return nil, err
+		}
+		for {
+			key, err := parser.sortKey()
+			if err != nil {
+				return nil, err
+			}
+			query.Sort = append(query.Sort, key)
+			if parser.peek().kind != tokenComma {
+				break
+			}
+			parser.next()
+		}
+	}
+	if parser.atKeyword("limit") {
+		parser.next()
+		if query.Limit, err = parser.count(); err != nil {
+			return nil, err
+		}
+	}
+	if parser.atKeyword("offset") {
+		parser.next()
+		if query.Offset, err = parser.count(); err != nil {
+			return nil, err
+		}
+	}
+	if token := parser.peek(); token.kind != tokenEnd {
+		return nil, token.errorf("unexpected %s", token)
+	}
+	return query, nil
+}
+
+// String returns the text the query was parsed from
+func (query *Query) String() string {
+	return query.text
+}
+
+// Match reports whether a salutation meets the query's conditions. Sort, Limit and Offset don't affect it
+func (query *Query) Match(salutation Salutation) bool {
+	return query.Where == nil || query.Where(salutation)
+}
+
+// Apply returns the salutations the query selects, sorted, with Offset skipped and at most Limit of them.
+// The result is a new slice - salutations isn't changed
+func (query *Query) Apply(salutations Salutations) Salutations {
+	result := Salutations{}
+	for _, salutation := range salutations {
+		if query.Match(salutation) {
+			result = append(result, salutation)
+		}
+	}
+	if len(query.Sort) > 0 {
+		sort.SliceStable(result, func(i, j int) bool {
+			for _, key := range query.Sort {
+				get, exists := queryFields[strings.ToLower(key.Field)]
+				if !exists {
+					continue
+				}
+				a, b := get(result[i]), get(result[j])
+				if a == b {
+					continue
+				}
+				return (a < b) != key.Descending
+			}
+			return false
+		})
+	}
+	result = result[min(query.Offset, len(result)):]
+	if query.Limit > 0 && query.Limit < len(result) {
+		result = result[:query.Limit]
+	}
+	return result
+}
+
+// Query parses text and applies it to the salutations
+func (salutations Salutations) Query(text string) (Salutations, error) {
+	query, err := ParseQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(salutations), nil
+}
+
+// Where returns the salutations match returns true for, as a new slice
+func (salutations Salutations) Where(match func(Salutation) bool) Salutations {
+	return (&Query{Where: match}).Apply(salutations)
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator // = != ~ !~
+	tokenOpen
+	tokenClose
+	tokenComma
+)
+
+type queryToken struct {
+	kind     tokenKind
+	text     string // the value of a string, otherwise the token as written
+	position int
+}
+
+func (token queryToken) String() string {
+	switch token.kind {
+	case tokenEnd:
+		return "end of query"
+	case tokenString:
+		return strconv.Quote(token.text)
+	default:
+		return "'" + token.text + "'"
+	}
+}
+
+func (token queryToken) errorf(format string, args ...interface{}) error {
+	return &QueryError{Position: token.position, Message: fmt.Sprintf(format, args...)}
+}
+
+// lexQuery splits a query into tokens, ending with a tokenEnd
+func lexQuery(text string) (tokens []queryToken, err error) {
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			kind := map[byte]tokenKind{'(': tokenOpen, ')': tokenClose, ',': tokenComma}[c]
+			tokens = append(tokens, queryToken{kind, text[i : i+1], i})
+			i++
+		case c == '=' || c == '~':
+			tokens = append(tokens, queryToken{tokenOperator, text[i : i+1], i})
+			i++
+		case c == '!':
+			if i+1 >= len(text) || (text[i+1] != '=' && text[i+1] != '~') {
+				return nil, &QueryError{Position: i, Message: "'!' must be followed by = or ~"}
+			}
+			tokens = append(tokens, queryToken{tokenOperator, text[i : i+2], i})
+			i += 2
+		case c == '"':
+			end := i + 1
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(text) {
+				return nil, &QueryError{Position: i, Message: "no closing \" for this string"}
+			}
+			value, err := strconv.Unquote(text[i : end+1])
+			if err != nil {
+				return nil, &QueryError{Position: i, Message: "bad escape in this string"}
+			}
+			tokens = append(tokens, queryToken{tokenString, value, i})
+			i = end + 1
+		case c == '\'':
+			end := strings.IndexByte(text[i+1:], '\'')
+			if end < 0 {
+				return nil, &QueryError{Position: i, Message: "no closing ' for this string"}
+			}
+			tokens = append(tokens, queryToken{tokenString, text[i+1 : i+1+end], i})
+			i += end + 2
+		default:
+			end := i
+			for end < len(text) && !strings.ContainsRune(" \t\n\r()=~!,\"'", rune(text[end])) {
+				end++
+			}
+			tokens = append(tokens, queryToken{tokenWord, text[i:end], i})
+			i = end
+		}
+	}
+	return append(tokens, queryToken{tokenEnd, "", len(text)}), nil
+}
+
+// queryParser is a recursive descent parser - each method parses one rule of the grammar
+type queryParser struct {
+	tokens []queryToken
+	at     int
+}
+
+func (parser *queryParser) peek() queryToken {
+	return parser.tokens[parser.at]
+}
+
+func (parser *queryParser) next() queryToken {
+	token := parser.tokens[parser.at]
+	if token.kind != tokenEnd {
+		parser.at++
+	}
+	return token
+}
+
+func (parser *queryParser) atKeyword(keywords ...string) bool {
+	token := parser.peek()
+	if token.kind != tokenWord {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(token.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (parser *queryParser) expectKeyword(keyword string) error {
+	if !parser.atKeyword(keyword) {
+		return parser.peek().errorf("expected %q, found %s", keyword, parser.peek())
+	}
+	parser.next()
+	return nil
+}
+
+// or parses conditions joined by "or"
+func (parser *queryParser) or() (func(Salutation) bool, error) {
+	left, err := parser.and()
+	if err != nil {
+		return nil, err
+	}
+	for parser.atKeyword("or") {
+		parser.next()
+		right, err := parser.and()
+		if err != nil {
+			return nil, err
+		}
+		first := left
+		left = func(s Salutation) bool { return first(s) || right(s) }
+	}
+	return left, nil
+}
+
+// and parses conditions joined by "and"
+func (parser *queryParser) and() (func(Salutation) bool, error) {
+	left, err := parser.unary()
+	if err != nil {
+		return nil, err
+	}
+	for parser.atKeyword("and") {
+		parser.next()
+		right, err := parser.unary()
+		if err != nil {
+			return nil, err
+		}
+		first := left
+		left = func(s Salutation) bool { return first(s) && right(s) }
+	}
+	return left, nil
+}
+
+// unary parses "not" a condition, a condition in parentheses, or a comparison
+func (parser *queryParser) unary() (func(Salutation) bool, error) {
+	switch {
+	case parser.atKeyword("not"):
+		parser.next()
+		inner, err := parser.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(s Salutation) bool { return !inner(s) }, nil
+	case parser.peek().kind == tokenOpen:
+		open := parser.next()
+		inner, err := parser.or()
+		if err != nil {
+			return nil, err
+		}
+		if parser.peek().kind != tokenClose {
+			return nil, open.errorf("no closing ) for this (")
+		}
+		parser.next()
+		return inner, nil
+	}
+	return parser.comparison()
+}
+
+// comparison parses field operator value
+func (parser *queryParser) comparison() (func(Salutation) bool, error) {
+	get, _, err := parser.field()
+	if err != nil {
+		return nil, err
+	}
+	operator := parser.next()
+	if operator.kind != tokenOperator {
+		return nil, operator.errorf("expected =, !=, ~ or !~, found %s", operator)
+	}
+	value := parser.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, value.errorf("expected a value, found %s", value)
+	}
+	switch operator.text {
+	case "=":
+		return func(s Salutation) bool { return get(s) == value.text }, nil
+	case "!=":
+		return func(s Salutation) bool { return get(s) != value.text }, nil
+	}
+	pattern, err := regexp.Compile(value.text)
+	if err != nil {
+		return nil, value.errorf("bad regexp: %v", err)
+	}
+	if operator.text == "!~" {
+		return func(s Salutation) bool { return !pattern.MatchString(get(s)) }, nil
+	}
+	return func(s Salutation) bool { return pattern.MatchString(get(s)) }, nil
+}
+
+// field parses a field name, returning the function that reads it and its name as written
+func (parser *queryParser) field() (func(Salutation) string, string, error) {
+	token := parser.next()
+	if token.kind == tokenWord {
+		if get, exists := queryFields[strings.ToLower(token.text)]; exists {
+			return get, token.text, nil
+		}
+	}
+	return nil, "", token.errorf("expected a field - name, casual or formal - found %s", token)
+}
+
+// sortKey parses a field with an optional asc or desc
+func (parser *queryParser) sortKey() (SortKey, error) {
+	_, name, err := parser.field()
+	if err != nil {
+		return SortKey{}, err
+	}
+	key := SortKey{Field: name}
+	switch {
+	case parser.atKeyword("asc"):
+		parser.next()
+	case parser.atKeyword("desc"):
+		parser.next()
+		key.Descending = true
+	}
+	return key, nil
+}
+
+// count parses the number after limit or offset
+func (parser *queryParser) count() (int, error) {
+	token := parser.next()
+	n, err := strconv.Atoi(token.text)
+	if token.kind != tokenWord || err != nil || n < 0 || !unicode.IsDigit(rune(token.text[0])) {
+		return 0, token.errorf("expected a number, found %s", token)
+	}
+	return n, nil
+}
@@ -0,0 +1,160 @@
+package goInterfaces
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+var queryRoster = Salutations{
+	{"Annica", "Howdy", "Hello"},
+	{"Mitchel", "Hey", "Hello"},
+	{"Marisol", "Salud", "Good day"},
+	{"Bob", "Hey", "Good day"},
+	{"Joline", "Howdy", "Greetings"},
+}
+
+// names returns the names of the salutations text selects from queryRoster
+func names(t *testing.T, text string) []string {
+	t.Helper()
+	selected, err := queryRoster.Query(text)
+	if err != nil {
+		t.Fatalf("Query(%q) = %v", text, err)
+	}
+	names := []string{}
+	for _, salutation := range selected {
+		names = append(names, salutation.Name)
+	}
+	return names
+}
+
+func TestQueryConditions(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Annica", "Mitchel", "Marisol", "Bob", "Joline"}},
+		{"casual = Hey", []string{"Mitchel", "Bob"}},
+		{"casual != Hey", []string{"Annica", "Marisol", "Joline"}},
+		{`name ~ "^M"`, []string{"Mitchel", "Marisol"}},
+		{`name !~ '(?i)^m'`, []string{"Annica", "Bob", "Joline"}},
+		{`formal = "Good day"`, []string{"Marisol", "Bob"}},
+		{`FormalGreeting = 'Good day' AND Name = Bob`, []string{"Bob"}},
+		{`name = "An\x6eica"`, []string{"Annica"}},
+		{"name = Nobody", []string{}},
+	}
+	for _, test := range tests {
+		if got := names(t, test.query); !slices.Equal(got, test.want) {
+			t.Errorf("%q selected %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestQueryPrecedence(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// and binds tighter than or: casual = Salud or (casual = Hey and formal = Hello)
+		{"casual = Salud or casual = Hey and formal = Hello", []string{"Mitchel", "Marisol"}},
+		{"casual = Hey and formal = Hello or casual = Salud", []string{"Mitchel", "Marisol"}},
+		{"(casual = Salud or casual = Hey) and formal = Hello", []string{"Mitchel"}},
+		// not binds tighter than and: (not casual = Hey) and formal = Hello
+		{"not casual = Hey and formal = Hello", []string{"Annica"}},
+		{"not (casual = Hey and formal = Hello)", []string{"Annica", "Marisol", "Bob", "Joline"}},
+		// and tighter than or: (not casual = Howdy) or name = Annica
+		{"not casual = Howdy or name = Annica", []string{"Annica", "Mitchel", "Marisol", "Bob"}},
+		{"not not name = Bob", []string{"Bob"}},
+		{"name = Bob or name = Joline or name = Annica and formal = Greetings", []string{"Bob", "Joline"}},
+	}
+	for _, test := range tests {
+		if got := names(t, test.query); !slices.Equal(got, test.want) {
+			t.Errorf("%q selected %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestQuerySortLimitOffset(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"sort by name", []string{"Annica", "Bob", "Joline", "Marisol", "Mitchel"}},
+		{"sort by name desc", []string{"Mitchel", "Marisol", "Joline", "Bob", "Annica"}},
+		// ties keep the roster's order, then the next key breaks them
+		{"sort by casual", []string{"Mitchel", "Bob", "Annica", "Joline", "Marisol"}},
+		{"sort by casual asc, name desc", []string{"Mitchel", "Bob", "Joline", "Annica", "Marisol"}},
+		{"limit 2", []string{"Annica", "Mitchel"}},
+		{"limit 0", []string{"Annica", "Mitchel", "Marisol", "Bob", "Joline"}},
+		{"limit 1", []string{"Annica"}},
+		{"limit 5", []string{"Annica", "Mitchel", "Marisol", "Bob", "Joline"}},
+		{"limit 99", []string{"Annica", "Mitchel", "Marisol", "Bob", "Joline"}},
+		{"offset 0", []string{"Annica", "Mitchel", "Marisol", "Bob", "Joline"}},
+		{"offset 4", []string{"Joline"}},
+		{"offset 5", []string{}},
+		{"offset 99", []string{}},
+		{"limit 2 offset 4", []string{"Joline"}},
+		{"sort by name limit 2 offset 1", []string{"Bob", "Joline"}},
+		{"casual = Hey sort by name limit 1", []string{"Bob"}},
+	}
+	for _, test := range tests {
+		if got := names(t, test.query); !slices.Equal(got, test.want) {
+			t.Errorf("%q selected %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestQueryErrorPositions(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+	}{
+		{"nickname = Bob", 0},
+		{"name Bob", 5},
+		{"name =", 6},
+		{"name = Bob and", 14},
+		{"name ! Bob", 5},
+		{`name = "Bob`, 7},
+		{"name = 'Bob", 7},
+		{`name = "\q"`, 7},
+		{"name ~ '('", 7},
+		{"(name = Bob", 0},
+		{"name = Bob)", 10},
+		{"sort name", 5},
+		{"sort by", 7},
+		{"sort by nickname", 8},
+		{"limit", 5},
+		{"limit -1", 6},
+		{"limit +1", 6},
+		{"limit ten", 6},
+		{"offset 1 limit 1", 9},
+		{"name = Bob sort by name, ", 25},
+	}
+	for _, test := range tests {
+		_, err := ParseQuery(test.query)
+		var queryError *QueryError
+		if !errors.As(err, &queryError) || !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("ParseQuery(%q) = %v, want a *QueryError", test.query, err)
+			continue
+		}
+		if queryError.Position != test.position {
+			t.Errorf("ParseQuery(%q) failed at %d, want %d: %v", test.query, queryError.Position, test.position, err)
+		}
+	}
+}
+
+func TestQueryApplyLeavesTheRosterAlone(t *testing.T) {
+	roster := slices.Clone(queryRoster)
+	query, err := ParseQuery("sort by name desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected := query.Apply(roster)
+	selected[0].Name = "Changed"
+	if !slices.Equal(roster, queryRoster) {
+		t.Errorf("Apply changed the roster to %v", roster)
+	}
+	if query.String() != "sort by name desc" {
+		t.Errorf("String = %q", query.String())
+	}
+}
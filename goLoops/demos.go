@@ -0,0 +1,89 @@
+package goLoops
+
+import (
+	"context"
+	"io"
+
+	"github.com/annicaburns/learngo/codec"
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/paging"
+	"github.com/annicaburns/learngo/registry"
+	"github.com/annicaburns/learngo/store"
+)
+
+// Register the loop demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "goLoops.BasicForLoop",
+		Description: "a classic three part FOR loop",
+		Params:      []registry.Param{registry.TimesParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			BasicForLoopTo(w, args.Int("times"))
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goLoops.WhileLoop",
+		Description: "a FOR loop with only a condition",
+		Params:      []registry.Param{registry.TimesParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			WhileLoopTo(w, args.Int("times"))
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goLoops.InfiniteLoop",
+		Description: "a FOR loop that only ends with break",
+		Params:      []registry.Param{registry.NameParam, registry.GreetingParam, registry.TimesParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			InfiniteLoopTo(w, core.Salutation{Name: args.String("name"), CasualGreeting: args.String("greeting")}, args.Int("times"))
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goLoops.LoopWithContinue",
+		Description: "a FOR loop that uses continue to skip the even iterations",
+		Params:      []registry.Param{registry.NameParam, registry.GreetingParam, registry.TimesParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			LoopWithContinueTo(w, core.Salutation{Name: args.String("name"), CasualGreeting: args.String("greeting")}, args.Int("times"))
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goLoops.CollectionLoop",
+		Description: "a FOR loop with a range over a slice",
+		Params:      []registry.Param{registry.RosterParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			if args.String("roster") == "" {
+				CollectionLoopTo(w)
+				return nil
+			}
+			roster, err := store.LoadRoster(args.String("roster"), nil)
+			if err != nil {
+				return err
+			}
+			CollectionLoopOver(w, roster)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goLoops.IteratorLoop",
+		Description: "a FOR loop with a range over an iterator function",
+		Params:      []registry.Param{registry.RosterParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			if args.String("roster") == "" {
+				return IteratorLoopTo(w)
+			}
+			// a JSON roster is streamed from the file - a key/value store has to be loaded first
+			var source paging.Source = paging.FileSource{Path: args.String("roster")}
+			if _, err := codec.ForPath(args.String("roster")); err != nil {
+				roster, err := store.LoadRoster(args.String("roster"), nil)
+				if err != nil {
+					return err
+				}
+				source = paging.NewSliceSource(roster)
+			}
+			return IteratorLoopOver(context.Background(), w, source)
+		},
+	})
+}
@@ -1,43 +1,66 @@
 package goLoops
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 
-	"github.com/annicaburns/learngo/greeting"
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/paging"
 )
 
 // GO has only one looping keyword (for), but it's not true that there is only one type of loop.
 // Easy to create the equivalent of a while loop and a collection loop with the FOR keyword because elements are all optionsl;
 // https://golang.org/doc/effective_go.html#for
 
-func vendSalutation() (salutation greeting.Salutation) {
-	return greeting.Salutation{Name: "Annica", Greeting: "Hello"}
+func vendSalutation() (salutation core.Salutation) {
+	return core.Salutation{Name: "Annica", CasualGreeting: "Hello"}
 }
 
+// Every demo has a "To" variant that writes to any io.Writer (a file, a bytes.Buffer in a test, a network connection...)
+// The original function simply calls its "To" variant with os.Stdout
+
 // BasicForLoop demonstrates
 func BasicForLoop(times int) {
+	BasicForLoopTo(os.Stdout, times)
+}
+
+// BasicForLoopTo is BasicForLoop writing to w
+func BasicForLoopTo(w io.Writer, times int) {
 	sal := vendSalutation()
 	for i := 0; i < times; i++ {
-		fmt.Println(sal.Greeting+", ", sal.Name)
+		fmt.Fprintln(w, sal.Greeting(false)+", ", sal.Name)
 	}
 }
 
 // WhileLoop demonstrates a FOR loop with a condition
 func WhileLoop(times int) {
+	WhileLoopTo(os.Stdout, times)
+}
+
+// WhileLoopTo is WhileLoop writing to w
+func WhileLoopTo(w io.Writer, times int) {
 	salutation := vendSalutation()
 	i := 0
 	for i < times {
-		fmt.Println(salutation.Greeting+", ", salutation.Name)
+		fmt.Fprintln(w, salutation.Greeting(false)+", ", salutation.Name)
 		i++
 	}
 }
 
 // InfiniteLoop demonstrates a FOR loop that will never end unless you call the break keyword at some point
-func InfiniteLoop(salutation greeting.Salutation, times int) {
+func InfiniteLoop(salutation core.Salutation, times int) {
+	InfiniteLoopTo(os.Stdout, salutation, times)
+}
+
+// InfiniteLoopTo is InfiniteLoop writing to w
+func InfiniteLoopTo(w io.Writer, salutation core.Salutation, times int) {
 	i := 0
 	for {
 		i++
-		fmt.Println(salutation.Greeting+", ", salutation.Name)
+		fmt.Fprintln(w, salutation.Greeting(false)+", ", salutation.Name)
 		if i >= times {
 			break
 		}
@@ -47,7 +70,12 @@ func InfiniteLoop(salutation greeting.Salutation, times int) {
 // LoopWithContinue demonstrates the use of the continue keyword
 // continue short circuts the loop so that all code in the loop below the continue keyword does not get executed, meanwhile the loop starts again
 // with a times param of 6 - this should only print 3 of them because only 3 are odd numbers
-func LoopWithContinue(salutation greeting.Salutation, times int) {
+func LoopWithContinue(salutation core.Salutation, times int) {
+	LoopWithContinueTo(os.Stdout, salutation, times)
+}
+
+// LoopWithContinueTo is LoopWithContinue writing to w
+func LoopWithContinueTo(w io.Writer, salutation core.Salutation, times int) {
 	i := 0
 	for {
 		if i >= times {
@@ -57,7 +85,7 @@ func LoopWithContinue(salutation greeting.Salutation, times int) {
 			i++
 			continue
 		}
-		fmt.Println(salutation.Greeting+", ", salutation.Name)
+		fmt.Fprintln(w, salutation.Greeting(false)+", ", salutation.Name)
 		i++
 	}
 }
@@ -69,12 +97,50 @@ func LoopWithContinue(salutation greeting.Salutation, times int) {
 //map
 //channel (waiting for some data to come into the channel)
 func CollectionLoop() {
-	slice := []greeting.Salutation{
-		{Name: "Annica", Greeting: "Hello"},
-		{Name: "Mitchel", Greeting: "Hi"},
+	CollectionLoopTo(os.Stdout)
+}
+
+// CollectionLoopTo is CollectionLoop writing to w
+func CollectionLoopTo(w io.Writer) {
+	slice := core.Salutations{
+		{Name: "Annica", CasualGreeting: "Hello"},
+		{Name: "Mitchel", CasualGreeting: "Hi"},
 	}
+	CollectionLoopOver(w, slice)
+}
+
+// CollectionLoopOver is CollectionLoop ranging over any roster of salutations
+func CollectionLoopOver(w io.Writer, slice core.Salutations) {
 	for _, s := range slice {
-		fmt.Println(s.Greeting+", ", s.Name)
+		fmt.Fprintln(w, s.Greeting(false)+", ", s.Name)
+
+	}
+}
 
+// IteratorLoop demonstrates a FOR loop with a range over a function (Go 1.23)
+// a function such as iter.Seq, which calls yield with each value, can be ranged over like a slice -
+// here it reads the salutations from an iterator one at a time, so the roster never has to be in a slice
+func IteratorLoop() {
+	if err := IteratorLoopTo(os.Stdout); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// IteratorLoopTo is IteratorLoop writing to w
+func IteratorLoopTo(w io.Writer) error {
+	source := paging.ChannelSource{Salutations: goInterfaces.VendSalutations()}
+	return IteratorLoopOver(context.Background(), w, source)
+}
+
+// IteratorLoopOver is IteratorLoop ranging over any paging.Source
+func IteratorLoopOver(ctx context.Context, w io.Writer, source paging.Source) error {
+	it, err := source.Iterate(ctx, "")
+	if err != nil {
+		return err
+	}
+	for s := range paging.Values(it) {
+		fmt.Fprintln(w, s.Greeting(false)+", ", s.Name)
 	}
+	// the loop can't return an error, so ask the iterator whether it stopped early
+	return it.Err()
 }
@@ -0,0 +1,58 @@
+package goMaps
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/annicaburns/learngo/registry"
+)
+
+var prefixParam = registry.Param{Name: "prefix", Kind: registry.String, Default: "Dr ", Description: "prefix to give the name"}
+
+// Register the map demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "goMaps.MapBasic",
+		Description: "look up a prefix in a map built with make",
+		Params:      []registry.Param{registry.NameParam},
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			_, err = fmt.Fprintln(w, MapBasic(args.String("name")))
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goMaps.MapUpdate",
+		Description: "look up a prefix after updating a map literal",
+		Params:      []registry.Param{registry.NameParam},
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			_, err = fmt.Fprintln(w, MapUpdate(args.String("name")))
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goMaps.MapDelete",
+		Description: "look up a prefix after deleting from a map, checking for existence",
+		Params:      []registry.Param{registry.NameParam},
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			_, err = fmt.Fprintln(w, MapDelete(args.String("name")))
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goMaps.SavePrefixes",
+		Description: "set a prefix in the shared registry, watch the change and save it as JSON",
+		Params:      []registry.Param{registry.NameParam, prefixParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			cancel := Prefixes.Watch(func(change PrefixChange) {
+				if change.Created {
+					fmt.Fprintf(w, "added %s with %q\n", change.Name, change.New)
+					return
+				}
+				fmt.Fprintf(w, "changed %s from %q to %q\n", change.Name, change.Old, change.New)
+			})
+			defer cancel()
+			Prefixes.Set(args.String("name"), args.String("prefix"))
+			return Prefixes.Save(w)
+		},
+	})
+}
@@ -9,7 +9,7 @@ import (
 // The type used for a map key in GO needs to have the equality operator defined for it
 // slice and map types do not have the equality operator defined and can't be used
 // Maps are reference types - behaves like a pointer
-// Maps are not thread safe - avoid using maps concurrently
+// Maps are not thread safe - avoid using maps concurrently (or guard them with a mutex - see PrefixRegistry)
 // Can insert, update, delete, check for existence
 // https://golang.org/doc/effective_go.html#maps
 
@@ -22,10 +22,11 @@ func MapBasic(name string) (prefix string) {
 	var prefixMap map[string]string
 	prefixMap = make(map[string]string)
 
-	prefixMap["Annica"] = "Ms "
-	prefixMap["Mitchel"] = "Mr "
-	prefixMap["Joline"] = "Mrs "
-	prefixMap["Jo"] = "Mr "
+	// insert every prefix from the shared registry
+	Prefixes.Range(func(registeredName, registeredPrefix string) bool {
+		prefixMap[registeredName] = registeredPrefix
+		return true
+	})
 
 	return prefixMap[name]
 
@@ -33,13 +34,9 @@ func MapBasic(name string) (prefix string) {
 
 // MapUpdate demonstrates a shorthand way to initialize and define a map and how to update a map
 // Update and Insert use the same syntax
+// The map is a snapshot (a copy) of the shared registry, so updating it doesn't change anybody else's prefixes
 func MapUpdate(name string) (prefix string) {
-	prefixMap := map[string]string{
-		"Annica":  "Ms ",
-		"Mitchel": "Mr ",
-		"Joline":  "Mrs ",
-		"Jo":      "Mr ",
-	}
+	prefixMap := Prefixes.Snapshot()
 	// update our map
 	prefixMap["Jo"] = "Mrs "
 
@@ -48,12 +45,7 @@ func MapUpdate(name string) (prefix string) {
 
 // MapDelete demonstrates how to delete a member from a map and how to check for existence
 func MapDelete(name string) (prefix string) {
-	prefixMap := map[string]string{
-		"Annica":  "Ms ",
-		"Mitchel": "Mr ",
-		"Joline":  "Mrs ",
-		"Jo":      "Mr ",
-	}
+	prefixMap := Prefixes.Snapshot()
 	// delete a member from our map
 	delete(prefixMap, "Jo")
 
@@ -61,5 +53,5 @@ func MapDelete(name string) (prefix string) {
 		return value
 	}
 
-	return "Dude "
+	return Prefixes.Fallback()
 }
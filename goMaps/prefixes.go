@@ -0,0 +1,271 @@
+package goMaps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Maps aren't safe to use from several goroutines at once, so PrefixRegistry wraps one in a sync.RWMutex.
+// Many readers can hold the read lock together, while a writer waits for the write lock and holds it alone.
+// Prefixes is the registry shared by the whole program - configure it once (or load it from JSON)
+// and goSwitch and greeting pick up the same prefixes.
+
+// DefaultPrefix is returned by Get for a name that has no prefix
+const DefaultPrefix = "Dude "
+
+// PrefixChange describes a single change to a PrefixRegistry. Old is empty for a new name and New is empty for a delete
+type PrefixChange struct {
+	Name    string
+	Old     string
+	New     string
+	Created bool // the name had no prefix before, rather than an empty one
+	Deleted bool
+}
+
+// PrefixRegistry maps a name to its prefix ("Annica" -> "Ms ") and is safe to use from several goroutines
+type PrefixRegistry struct {
+	mutex       sync.RWMutex
+	prefixes    map[string]string
+	fallback    string
+	watchers    map[int]func(PrefixChange)
+	nextWatcher int
+	pending     []pendingChange // changes made but not yet passed to the watchers, oldest first
+	delivering  bool            // a goroutine is passing the pending changes to the watchers
+}
+
+// pendingChange is a change waiting to be passed to the watchers that were watching when it was made
+type pendingChange struct {
+	change   PrefixChange
+	watchers []int
+}
+
+// NewPrefixRegistry creates a registry holding a copy of prefixes, returning fallback for any other name
+func NewPrefixRegistry(fallback string, prefixes map[string]string) *PrefixRegistry {
+	registry := &PrefixRegistry{prefixes: make(map[string]string, len(prefixes)), fallback: fallback, watchers: make(map[int]func(PrefixChange))}
+	for name, prefix := range prefixes {
+		registry.prefixes[name] = prefix
+	}
+	return registry
+}
+
+// Prefixes is the prefix registry shared by goMaps, goSwitch and greeting
+var Prefixes = NewPrefixRegistry(DefaultPrefix, map[string]string{
+	"Annica":  "Ms ",
+	"Mitchel": "Mr ",
+	"Joline":  "Mrs ",
+	"Jo":      "Mr ",
+})
+
+// Get returns the prefix for name, or the fallback if it doesn't have one
+func (registry *PrefixRegistry) Get(name string) string {
+	if prefix, exists := registry.Lookup(name); exists {
+		return prefix
+	}
+	return registry.Fallback()
+}
+
+// Lookup returns the prefix for name and whether it has one - just like reading a map with the "comma ok" form
+func (registry *PrefixRegistry) Lookup(name string) (prefix string, exists bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	prefix, exists = registry.prefixes[name]
+	return
+}
+
+// Exists reports whether name has a prefix
+func (registry *PrefixRegistry) Exists(name string) bool {
+	_, exists := registry.Lookup(name)
+	return exists
+}
+
+// Set inserts or updates the prefix for name, reporting whether name already had one.
+// Setting the prefix a name already has changes nothing, so the watchers aren't told
+func (registry *PrefixRegistry) Set(name, prefix string) (existed bool) {
+	registry.mutex.Lock()
+	old, existed := registry.prefixes[name]
+	if existed && old == prefix {
+		registry.mutex.Unlock()
+		return true
+	}
+	registry.prefixes[name] = prefix
+	registry.notify(PrefixChange{Name: name, Old: old, New: prefix, Created: !existed})
+	return existed
+}
+
+// Delete removes the prefix for name, reporting whether there was one to remove
+func (registry *PrefixRegistry) Delete(name string) bool {
+	registry.mutex.Lock()
+	old, exists := registry.prefixes[name]
+	if !exists {
+		registry.mutex.Unlock()
+		return false
+	}
+	delete(registry.prefixes, name)
+	registry.notify(PrefixChange{Name: name, Old: old, Deleted: true})
+	return true
+}
+
+// Len returns the number of names with a prefix
+func (registry *PrefixRegistry) Len() int {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return len(registry.prefixes)
+}
+
+// Fallback returns the prefix Get uses for a name without one
+func (registry *PrefixRegistry) Fallback() string {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.fallback
+}
+
+// SetFallback changes the prefix Get uses for a name without one
+func (registry *PrefixRegistry) SetFallback(fallback string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.fallback = fallback
+}
+
+// Snapshot returns a copy of every prefix. Changing the copy doesn't change the registry
+func (registry *PrefixRegistry) Snapshot() map[string]string {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	snapshot := make(map[string]string, len(registry.prefixes))
+	for name, prefix := range registry.prefixes {
+		snapshot[name] = prefix
+	}
+	return snapshot
+}
+
+// Range calls f for every name in alphabetical order until f returns false.
+// It ranges over a snapshot, so f is free to change the registry
+func (registry *PrefixRegistry) Range(f func(name, prefix string) bool) {
+	snapshot := registry.Snapshot()
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !f(name, snapshot[name]) {
+			return
+		}
+	}
+}
+
+// Watch calls f after every change to the registry until the returned cancel function is called.
+// Changes are passed to f one at a time, in the order they were made. f runs on the goroutine that made
+// the change - or, when several goroutines change the registry at once, on one of them - so it should return quickly.
+// f may change the registry itself: that change is passed on once f returns
+func (registry *PrefixRegistry) Watch(f func(PrefixChange)) (cancel func()) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	id := registry.nextWatcher
+	registry.nextWatcher++
+	registry.watchers[id] = f
+	return func() {
+		registry.mutex.Lock()
+		defer registry.mutex.Unlock()
+		delete(registry.watchers, id)
+	}
+}
+
+// notify queues change for the current watchers and unlocks the registry. The write lock must be held.
+// Changes are queued in the order they are made while the lock is held, and only one goroutine at a time
+// empties the queue, so no watcher sees a later change before an earlier one
+func (registry *PrefixRegistry) notify(change PrefixChange) {
+	ids := make([]int, 0, len(registry.watchers))
+	for id := range registry.watchers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	registry.pending = append(registry.pending, pendingChange{change: change, watchers: ids})
+	if registry.delivering {
+		// the goroutine already emptying the queue will get to this change too
+		registry.mutex.Unlock()
+		return
+	}
+	registry.delivering = true
+	for len(registry.pending) > 0 {
+		next := registry.pending[0]
+		registry.pending = registry.pending[1:]
+		// a watcher cancelled since the change was made isn't called
+		var watchers []func(PrefixChange)
+		for _, id := range next.watchers {
+			if watcher, exists := registry.watchers[id]; exists {
+				watchers = append(watchers, watcher)
+			}
+		}
+		registry.mutex.Unlock()
+		for _, watcher := range watchers {
+			watcher(next.change)
+		}
+		registry.mutex.Lock()
+	}
+	registry.pending, registry.delivering = nil, false
+	registry.mutex.Unlock()
+}
+
+// prefixFile is the JSON layout used by Load and Save
+type prefixFile struct {
+	Fallback string            `json:"fallback"`
+	Prefixes map[string]string `json:"prefixes"`
+}
+
+// Save writes the fallback and every prefix to w as JSON
+func (registry *PrefixRegistry) Save(w io.Writer) error {
+	file := prefixFile{Fallback: registry.Fallback(), Prefixes: registry.Snapshot()}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(file); err != nil {
+		return fmt.Errorf("goMaps: saving prefixes: %w", err)
+	}
+	return nil
+}
+
+// Load reads JSON written by Save, setting the fallback and every prefix in it. Names not in the JSON are left alone
+func (registry *PrefixRegistry) Load(r io.Reader) error {
+	var file prefixFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return fmt.Errorf("goMaps: loading prefixes: %w", err)
+	}
+	if file.Fallback != "" {
+		registry.SetFallback(file.Fallback)
+	}
+	names := make([]string, 0, len(file.Prefixes))
+	for name := range file.Prefixes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		registry.Set(name, file.Prefixes[name])
+	}
+	return nil
+}
+
+// SaveFile writes the registry to the JSON file at path
+func (registry *PrefixRegistry) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("goMaps: saving prefixes: %w", err)
+	}
+	if err := registry.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadFile reads the JSON file at path into the registry
+func (registry *PrefixRegistry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("goMaps: loading prefixes: %w", err)
+	}
+	defer f.Close()
+	return registry.Load(f)
+}
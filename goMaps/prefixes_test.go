@@ -0,0 +1,243 @@
+package goMaps
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newRegistry() *PrefixRegistry {
+	return NewPrefixRegistry(DefaultPrefix, map[string]string{"Annica": "Ms ", "Mitchel": "Mr "})
+}
+
+func TestGetFallsBack(t *testing.T) {
+	registry := newRegistry()
+	tests := map[string]string{"Annica": "Ms ", "Mitchel": "Mr ", "Bob": "Dude ", "": "Dude ", "annica": "Dude "}
+	for name, want := range tests {
+		if got := registry.Get(name); got != want {
+			t.Errorf("Get(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if _, exists := registry.Lookup("Bob"); exists {
+		t.Error("Lookup found a prefix for Bob")
+	}
+	// an empty prefix is still a prefix, so it isn't replaced by the fallback
+	registry.Set("Bob", "")
+	if got, exists := registry.Lookup("Bob"); got != "" || !exists || registry.Get("Bob") != "" {
+		t.Errorf("after setting an empty prefix Lookup = %q, %t and Get = %q", got, exists, registry.Get("Bob"))
+	}
+	registry.SetFallback("Hey ")
+	if got := registry.Get("Cara"); got != "Hey " {
+		t.Errorf("Get after SetFallback = %q", got)
+	}
+}
+
+func TestSetReportsWhetherTheNameExisted(t *testing.T) {
+	registry := newRegistry()
+	if registry.Set("Bob", "Dr ") {
+		t.Error("Set of a new name says it existed")
+	}
+	if !registry.Set("Bob", "Prof ") || !registry.Set("Bob", "Prof ") {
+		t.Error("Set of an existing name says it didn't exist")
+	}
+	if !registry.Delete("Bob") || registry.Delete("Bob") {
+		t.Error("Delete didn't report whether Bob had a prefix")
+	}
+	if registry.Len() != 2 {
+		t.Errorf("Len = %d, want 2", registry.Len())
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	registry := newRegistry()
+	var wg sync.WaitGroup
+	created := make([]int, 10)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				name := fmt.Sprintf("Person %d", i%10)
+				registry.Get(name)
+				registry.Snapshot()
+				registry.Delete(name)
+				registry.Get("Annica")
+			}
+		}()
+	}
+	// every name goes from missing to present exactly once, however the Sets race
+	var mutex sync.Mutex
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if !registry.Set(fmt.Sprintf("Name %d", i), fmt.Sprint(g)) {
+					mutex.Lock()
+					created[i]++
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for i, count := range created {
+		if count != 1 {
+			t.Errorf("Set created Name %d %d times, want once", i, count)
+		}
+	}
+	if got := registry.Get("Annica"); got != "Ms " {
+		t.Errorf("Annica's prefix became %q", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	registry := newRegistry()
+	var changes []PrefixChange
+	cancel := registry.Watch(func(change PrefixChange) { changes = append(changes, change) })
+	registry.Set("Bob", "")
+	registry.Set("Bob", "Dr ")
+	registry.Set("Bob", "Dr ") // no change, so no notification
+	registry.Delete("Bob")
+	registry.Delete("Bob") // nothing to delete
+	cancel()
+	registry.Set("Cara", "Ms ")
+	want := []PrefixChange{
+		{Name: "Bob", Created: true},
+		{Name: "Bob", New: "Dr "},
+		{Name: "Bob", Old: "Dr ", Deleted: true},
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("the watcher saw %+v, want %+v", changes, want)
+	}
+}
+
+func TestWatchersSeeChangesInTheOrderTheyWereMade(t *testing.T) {
+	registry := newRegistry()
+	var changes []PrefixChange // only ever appended to by one goroutine at a time
+	registry.Watch(func(change PrefixChange) { changes = append(changes, change) })
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				registry.Set("Bob", fmt.Sprintf("%d-%d", g, i))
+			}
+		}()
+	}
+	wg.Wait()
+	if len(changes) != 800 {
+		t.Fatalf("the watcher saw %d changes, want 800", len(changes))
+	}
+	// in commit order each change starts from where the one before it ended, and the last is the registry's value
+	if !changes[0].Created {
+		t.Errorf("the first change %+v didn't create Bob", changes[0])
+	}
+	for i := 1; i < len(changes); i++ {
+		if changes[i].Old != changes[i-1].New || changes[i].Created {
+			t.Fatalf("change %d %+v doesn't follow %+v", i, changes[i], changes[i-1])
+		}
+	}
+	if last := changes[len(changes)-1].New; last != registry.Get("Bob") {
+		t.Errorf("the watcher last saw %q, but Bob's prefix is %q", last, registry.Get("Bob"))
+	}
+}
+
+func TestWatcherCanChangeTheRegistry(t *testing.T) {
+	registry := newRegistry()
+	var changes []string
+	registry.Watch(func(change PrefixChange) {
+		changes = append(changes, change.Name+"="+change.New)
+		if change.Name == "Bob" {
+			registry.Set("Bob's friend", "Dr ")
+		}
+	})
+	registry.Set("Bob", "Mr ")
+	if want := []string{"Bob=Mr ", "Bob's friend=Dr "}; !slices.Equal(changes, want) {
+		t.Errorf("the watcher saw %q, want %q", changes, want)
+	}
+}
+
+func TestSnapshotAndRangeAreCopies(t *testing.T) {
+	registry := newRegistry()
+	snapshot := registry.Snapshot()
+	snapshot["Annica"] = "Dr "
+	delete(snapshot, "Mitchel")
+	snapshot["Bob"] = "Mr "
+	if want := map[string]string{"Annica": "Ms ", "Mitchel": "Mr "}; !maps.Equal(registry.Snapshot(), want) {
+		t.Errorf("changing the snapshot changed the registry to %v", registry.Snapshot())
+	}
+	var names []string
+	registry.Range(func(name, prefix string) bool {
+		names = append(names, name)
+		// Range works from a snapshot, so this neither deadlocks nor shows up in the loop
+		registry.Set("Zed", "Mx ")
+		registry.Delete("Mitchel")
+		return true
+	})
+	if want := []string{"Annica", "Mitchel"}; !slices.Equal(names, want) {
+		t.Errorf("Range visited %q, want %q", names, want)
+	}
+	names = nil
+	registry.Range(func(name, prefix string) bool {
+		names = append(names, name)
+		return false
+	})
+	if len(names) != 1 {
+		t.Errorf("Range carried on after f returned false: %q", names)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	registry := newRegistry()
+	registry.SetFallback("Hey ")
+	path := filepath.Join(t.TempDir(), "prefixes.json")
+	if err := registry.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewPrefixRegistry(DefaultPrefix, map[string]string{"Cara": "Dr "})
+	var created []string
+	loaded.Watch(func(change PrefixChange) {
+		if change.Created {
+			created = append(created, change.Name)
+		}
+	})
+	if err := loaded.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	// names the file doesn't mention are left alone
+	if want := map[string]string{"Annica": "Ms ", "Mitchel": "Mr ", "Cara": "Dr "}; !maps.Equal(loaded.Snapshot(), want) {
+		t.Errorf("loaded %v, want %v", loaded.Snapshot(), want)
+	}
+	if loaded.Fallback() != "Hey " {
+		t.Errorf("loaded the fallback %q", loaded.Fallback())
+	}
+	if want := []string{"Annica", "Mitchel"}; !slices.Equal(created, want) {
+		t.Errorf("loading told the watcher about %q, want %q in order", created, want)
+	}
+
+	var saved bytes.Buffer
+	if err := registry.Save(&saved); err != nil || !strings.Contains(saved.String(), `"Annica": "Ms "`) {
+		t.Errorf("Save = %v, wrote\n%s", err, &saved)
+	}
+	for _, malformed := range []string{"", "{", `{"prefixes": ["Ms "]}`, `{"fallback": 3}`} {
+		before := loaded.Snapshot()
+		if err := loaded.Load(strings.NewReader(malformed)); err == nil || !strings.HasPrefix(err.Error(), "goMaps: loading prefixes") {
+			t.Errorf("Load(%q) = %v", malformed, err)
+		}
+		if !maps.Equal(loaded.Snapshot(), before) {
+			t.Errorf("a failed Load(%q) changed the registry", malformed)
+		}
+	}
+	if err := loaded.LoadFile(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadFile of a missing file = %v", err)
+	}
+}
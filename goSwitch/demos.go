@@ -0,0 +1,126 @@
+package goSwitch
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/goMaps"
+	"github.com/annicaburns/learngo/registry"
+)
+
+// Parameters only the rule demo uses
+var (
+	titleParam  = registry.Param{Name: "title", Kind: registry.String, Default: "", Description: "title of the person, such as Doctor"}
+	localeParam = registry.Param{Name: "locale", Kind: registry.String, Default: "", Description: "locale of the person, such as es-MX"}
+	rulesParam  = registry.Param{Name: "rules", Kind: registry.String, Default: "", Description: "JSON rules file - empty uses FallthroughRules"}
+	traceParam  = registry.Param{Name: "trace", Kind: registry.Bool, Default: "false", Description: "explain which rule fired"}
+	valueParam  = registry.Param{Name: "value", Kind: registry.String, Default: "salutation", Description: "example to describe: " + strings.Join(exampleNames(), ", ")}
+)
+
+// examples are the values the Describe demo can describe
+var examples = map[string]func(args registry.Args) interface{}{
+	"int":    func(args registry.Args) interface{} { return 42 },
+	"string": func(args registry.Args) interface{} { return args.String("name") },
+	"salutation": func(args registry.Args) interface{} {
+		return core.Salutation{Name: args.String("name"), CasualGreeting: "Howdy"}
+	},
+	"salutations":  func(args registry.Args) interface{} { return goInterfaces.VendSalutations() },
+	"pointer":      func(args registry.Args) interface{} { return &goInterfaces.Salutation{Name: args.String("name")} },
+	"map":          func(args registry.Args) interface{} { return goMaps.Prefixes.Snapshot() },
+	"channel":      func(args registry.Args) interface{} { return make(chan goInterfaces.Salutation, 3) },
+	"slice":        func(args registry.Args) interface{} { return []int{1, 2, 3} },
+	"unregistered": func(args registry.Args) interface{} { return Subject{Name: args.String("name"), Locale: "en"} },
+}
+
+func exampleNames() (names []string) {
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Register the switch demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "goSwitch.SwitchBasic",
+		Description: "choose a prefix with a basic switch statement",
+		Params:      []registry.Param{registry.NameParam},
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			_, err = fmt.Fprintln(w, SwitchBasic(args.String("name")))
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goSwitch.SwitchFallthrough",
+		Description: "choose a prefix with a switch that uses fallthrough",
+		Params:      []registry.Param{registry.NameParam},
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			_, err = fmt.Fprintln(w, SwitchFallthrough(args.String("name")))
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goSwitch.SwitchPrefix",
+		Description: "choose a prefix by asking the shared prefix registry",
+		Params:      []registry.Param{registry.NameParam},
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			_, err = fmt.Fprintln(w, SwitchPrefix(args.String("name")))
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goSwitch.ResolvePrefix",
+		Description: "choose a prefix with an ordered rule set instead of a switch",
+		Params:      []registry.Param{registry.NameParam, titleParam, localeParam, rulesParam, traceParam},
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			rules := FallthroughRules
+			if path := args.String("rules"); path != "" {
+				if rules, err = LoadRulesFile(path); err != nil {
+					return err
+				}
+			}
+			resolution := rules.Resolve(Subject{Name: args.String("name"), Title: args.String("title"), Locale: args.String("locale")})
+			if args.Bool("trace") {
+				_, err = fmt.Fprint(w, resolution.Explain())
+				return
+			}
+			_, err = fmt.Fprintln(w, resolution.Prefix)
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goSwitch.Describe",
+		Description: "describe a value with the type dispatch registry instead of a type switch",
+		Params:      []registry.Param{valueParam, registry.NameParam},
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			example, exists := examples[args.String("value")]
+			if !exists {
+				return fmt.Errorf("%w: no example called %q", registry.ErrInvalidParam, args.String("value"))
+			}
+			_, err = fmt.Fprintln(w, Describe(example(args)))
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goSwitch.SwitchNothing",
+		Description: "a switch with no value - one big if/else",
+		Run: func(w io.Writer, args registry.Args) (err error) {
+			_, err = fmt.Fprintln(w, SwitchNothing())
+			return
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "goSwitch.SwitchType",
+		Description: "switch on the type of a core.Salutation",
+		Params:      []registry.Param{registry.NameParam, registry.GreetingParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			SwitchTypeTo(w, core.Salutation{Name: args.String("name"), CasualGreeting: args.String("greeting")})
+			return nil
+		},
+	})
+}
@@ -0,0 +1,243 @@
+package goSwitch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/greeting"
+)
+
+// SwitchType can only recognise the types written into its cases, and it prints rather than returning anything.
+// A Dispatcher is the extensible version: callers register a handler for any type (or for a whole kind of type,
+// such as every map) and Describe returns a structured Description instead of printing.
+// A value nobody registered a handler for is described with reflection - its kind, length, element type and fields.
+// Lookup order: the exact type, then (for a pointer) the type it points to, then the kind, then reflection.
+
+// Field describes one field of a struct
+type Field struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// Description is what a Dispatcher says about a value
+type Description struct {
+	Label    string // short name such as "salutation" - the same labels SwitchType prints
+	Type     string // the Go type, such as "goInterfaces.Salutation"
+	Kind     string // the reflect kind, such as "struct" or "slice"
+	Len      int    // the length of a string, slice, array, map or channel
+	Cap      int    // the capacity of a slice or channel
+	ElemType string // the element type of a pointer, slice, array, map or channel
+	KeyType  string // the key type of a map
+	Fields   []Field
+	Nil      bool
+	Summary  string // a one line summary of the value
+	Handler  string // "registered", "kind" or "reflection" - which lookup produced the description
+}
+
+// String formats the description over one or more lines
+func (description Description) String() string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s (%s %s)", description.Label, description.Type, description.Kind)
+	if description.Summary != "" {
+		fmt.Fprintf(&text, ": %s", description.Summary)
+	}
+	for _, field := range description.Fields {
+		fmt.Fprintf(&text, "\n  %s %s = %s", field.Name, field.Type, field.Value)
+	}
+	return text.String()
+}
+
+// Handler describes a value of the type (or kind) it was registered for
+type Handler func(x interface{}) Description
+
+// Dispatcher holds the registered handlers. It is safe to use from several goroutines
+type Dispatcher struct {
+	mutex    sync.RWMutex
+	types    map[reflect.Type]Handler
+	kinds    map[reflect.Kind]Handler
+	fallback Handler
+}
+
+// NewDispatcher creates a dispatcher with no handlers, which describes everything with reflection
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{types: make(map[reflect.Type]Handler), kinds: make(map[reflect.Kind]Handler), fallback: Reflect}
+}
+
+// Register adds a handler for the type of example, replacing any handler already registered for it
+func (dispatcher *Dispatcher) Register(example interface{}, handler Handler) {
+	dispatcher.RegisterType(reflect.TypeOf(example), handler)
+}
+
+// RegisterType adds a handler for t, replacing any handler already registered for it
+func (dispatcher *Dispatcher) RegisterType(t reflect.Type, handler Handler) {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	dispatcher.types[t] = handler
+}
+
+// RegisterKind adds a handler for every type of a kind - every map or every channel for example
+func (dispatcher *Dispatcher) RegisterKind(kind reflect.Kind, handler Handler) {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	dispatcher.kinds[kind] = handler
+}
+
+// Handle registers a handler that receives the value already converted to T, so it doesn't need a type assertion
+func Handle[T any](dispatcher *Dispatcher, handler func(T) Description) {
+	dispatcher.RegisterType(reflect.TypeOf((*T)(nil)).Elem(), func(x interface{}) Description {
+		return handler(x.(T))
+	})
+}
+
+// Describe finds the handler for x and returns its description
+func (dispatcher *Dispatcher) Describe(x interface{}) Description {
+	if x == nil {
+		return Description{Label: "nil", Type: "nil", Kind: "invalid", Nil: true, Handler: "reflection"}
+	}
+	t := reflect.TypeOf(x)
+	dispatcher.mutex.RLock()
+	handler, exists := dispatcher.types[t]
+	kindHandler, kindExists := dispatcher.kinds[t.Kind()]
+	var elemHandler Handler
+	if t.Kind() == reflect.Pointer {
+		elemHandler = dispatcher.types[t.Elem()]
+	}
+	dispatcher.mutex.RUnlock()
+
+	switch value := reflect.ValueOf(x); {
+	case exists:
+		return fill(handler(x), value, "registered")
+	case elemHandler != nil && !value.IsNil():
+		// a pointer to a registered type is described like the value it points to
+		description := fill(elemHandler(value.Elem().Interface()), value.Elem(), "registered")
+		description.Label = "pointer to " + description.Label
+		description.ElemType = description.Type
+		description.Type, description.Kind = t.String(), t.Kind().String()
+		return fill(description, value, "registered")
+	case kindExists:
+		return fill(kindHandler(x), value, "kind")
+	default:
+		return dispatcher.fallback(x)
+	}
+}
+
+// fill completes whatever a handler left out of its description
+func fill(description Description, value reflect.Value, handler string) Description {
+	if description.Type == "" {
+		description.Type = value.Type().String()
+	}
+	if description.Kind == "" {
+		description.Kind = value.Kind().String()
+	}
+	if description.Label == "" {
+		description.Label = description.Kind
+	}
+	description.Handler = handler
+	return description
+}
+
+// Reflect describes any value using reflection alone. A pointer that leads back to itself,
+// such as x after x = &x, is summarised as a cycle rather than followed forever
+func Reflect(x interface{}) Description {
+	return reflectValue(x, nil)
+}
+
+// reflectValue is Reflect remembering the pointers already followed
+func reflectValue(x interface{}, followed map[uintptr]bool) (description Description) {
+	value := reflect.ValueOf(x)
+	if !value.IsValid() {
+		return Description{Label: "nil", Type: "nil", Kind: "invalid", Nil: true, Handler: "reflection"}
+	}
+	t := value.Type()
+	description = Description{Label: t.Kind().String(), Type: t.String(), Kind: t.Kind().String(), Handler: "reflection"}
+	switch t.Kind() {
+	case reflect.Pointer:
+		description.ElemType = t.Elem().String()
+		description.Nil = value.IsNil()
+		if !description.Nil {
+			if followed[value.Pointer()] {
+				description.Summary = "cycle - points back to a value already described"
+				break
+			}
+			if followed == nil {
+				followed = make(map[uintptr]bool)
+			}
+			followed[value.Pointer()] = true
+			description.Summary = "points to " + reflectValue(value.Elem().Interface(), followed).Summary
+		}
+	case reflect.Slice, reflect.Map, reflect.Chan:
+		description.ElemType = t.Elem().String()
+		description.Nil = value.IsNil()
+		description.Len = value.Len()
+		if t.Kind() == reflect.Map {
+			description.KeyType = t.Key().String()
+		} else {
+			description.Cap = value.Cap()
+		}
+		description.Summary = fmt.Sprintf("%d %s", description.Len, description.ElemType)
+	case reflect.Array:
+		description.ElemType = t.Elem().String()
+		description.Len = value.Len()
+		description.Summary = fmt.Sprintf("%d %s", description.Len, description.ElemType)
+	case reflect.String:
+		description.Len = value.Len()
+		description.Summary = fmt.Sprintf("%q", value.String())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			// fmt can print unexported fields through a reflect.Value, even though Interface() can't reach them
+			description.Fields = append(description.Fields, Field{Name: field.Name, Type: field.Type.String(), Value: fmt.Sprintf("%q", fmt.Sprint(value.Field(i)))})
+		}
+		description.Summary = fmt.Sprintf("%d fields", t.NumField())
+	case reflect.Func:
+		description.Nil = value.IsNil()
+	default:
+		description.Summary = fmt.Sprint(x)
+	}
+	return
+}
+
+// DefaultDispatcher knows every type SwitchType does, plus the other salutation types in learngo
+var DefaultDispatcher = NewDispatcher()
+
+func init() {
+	Handle(DefaultDispatcher, func(i int) Description {
+		return Description{Label: "int", Summary: fmt.Sprint(i)}
+	})
+	Handle(DefaultDispatcher, func(s string) Description {
+		return Description{Label: "string", Len: len(s), Summary: fmt.Sprintf("%q", s)}
+	})
+	Handle(DefaultDispatcher, func(s greeting.Salutation) Description {
+		return Description{Label: "salutation", Summary: s.Greeting + ", " + s.Name, Fields: Reflect(s).Fields}
+	})
+	Handle(DefaultDispatcher, func(s core.Salutation) Description {
+		return Description{Label: "salutation", Summary: s.Message(false), Fields: Reflect(s).Fields}
+	})
+	Handle(DefaultDispatcher, func(s goInterfaces.Salutation) Description {
+		return Description{Label: "salutation", Summary: s.CasualGreeting + ", " + s.Name, Fields: Reflect(s).Fields}
+	})
+	Handle(DefaultDispatcher, func(salutations goInterfaces.Salutations) Description {
+		return describeSalutations(len(salutations), cap(salutations), func(i int) string { return salutations[i].Name })
+	})
+	Handle(DefaultDispatcher, func(salutations core.Salutations) Description {
+		return describeSalutations(len(salutations), cap(salutations), func(i int) string { return salutations[i].Name })
+	})
+}
+
+func describeSalutations(length, capacity int, name func(i int) string) Description {
+	names := make([]string, length)
+	for i := range names {
+		names[i] = name(i)
+	}
+	return Description{Label: "salutations", Len: length, Cap: capacity, ElemType: "salutation", Summary: strings.Join(names, ", ")}
+}
+
+// Describe describes x with the DefaultDispatcher
+func Describe(x interface{}) Description {
+	return DefaultDispatcher.Describe(x)
+}
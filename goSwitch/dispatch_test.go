@@ -0,0 +1,52 @@
+package goSwitch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+func TestDescribeCycles(t *testing.T) {
+	var x interface{}
+	x = &x
+	description := Describe(x)
+	if !strings.Contains(description.Summary, "cycle") {
+		t.Errorf("Describe(x = &x) summary = %q, want a cycle", description.Summary)
+	}
+
+	type node struct{ next *node }
+	a, b := &node{}, &node{}
+	a.next, b.next = b, a
+	var pa, pb interface{}
+	pa, pb = &pb, &pa
+	if summary := Describe(pa).Summary; !strings.Contains(summary, "cycle") {
+		t.Errorf("Describe of a two pointer cycle = %q, want a cycle", summary)
+	}
+	// fmt prints the struct's pointer field as an address, so a cycle through a struct already ends
+	if description := Describe(a); description.Kind != "ptr" {
+		t.Errorf("Describe(&node) kind = %q", description.Kind)
+	}
+}
+
+func TestDescribeHandlers(t *testing.T) {
+	salutation := core.Salutation{Name: "Annica", CasualGreeting: "Hi"}
+	tests := []struct {
+		x       interface{}
+		label   string
+		handler string
+	}{
+		{42, "int", "registered"},
+		{"hi", "string", "registered"},
+		{salutation, "salutation", "registered"},
+		{&salutation, "pointer to salutation", "registered"},
+		{[]int{1, 2}, "slice", "reflection"},
+		{nil, "nil", "reflection"},
+	}
+	for _, test := range tests {
+		description := Describe(test.x)
+		if description.Label != test.label || description.Handler != test.handler {
+			t.Errorf("Describe(%#v) = %s from %s, want %s from %s", test.x, description.Label, description.Handler, test.label, test.handler)
+		}
+	}
+}
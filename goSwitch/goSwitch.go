@@ -2,7 +2,13 @@ package goSwitch
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/goMaps"
 	"github.com/annicaburns/learngo/greeting"
 )
 
@@ -11,6 +17,7 @@ import (
 // https://golang.org/doc/effective_go.html#switch
 
 // SwitchBasic demonstrates the basic switch statement in GO - If Annica evaluates to true, it will return "Ms""
+// BasicRules gives the same answers from a RuleSet, which can be changed without editing the code
 func SwitchBasic(name string) (prefix string) {
 	switch name {
 	case "Annica":
@@ -24,6 +31,7 @@ func SwitchBasic(name string) (prefix string) {
 }
 
 // SwitchFallthrough demonstrates using the fallthrough keyword - If Annica evaluates to true, it will return "Mr"
+// FallthroughRules gives the same answers from a RuleSet
 func SwitchFallthrough(name string) (prefix string) {
 	switch name {
 	case "Annica":
@@ -37,6 +45,18 @@ func SwitchFallthrough(name string) (prefix string) {
 	return
 }
 
+// SwitchPrefix demonstrates a switch with an initializer statement and no value.
+// It asks the shared goMaps.Prefixes registry, so a prefix configured there is picked up here too
+func SwitchPrefix(name string) (prefix string) {
+	switch registered, exists := goMaps.Prefixes.Lookup(name); {
+	case exists:
+		prefix = registered
+	default:
+		prefix = goMaps.Prefixes.Fallback()
+	}
+	return strings.TrimSpace(prefix)
+}
+
 // SwitchNothing demonstrates the fact that you don't have to switch on a value.
 // The cases will each become an expression, and the first case that evaluates to true will be executed
 // So it's basically just one big if/else statement
@@ -61,14 +81,19 @@ func SwitchNothing() (result string) {
 // SwitchType demonstrates switching on a type
 // "interface{}" means the input parameter can be of any type - like Any in Swift
 func SwitchType(x interface{}) {
+	SwitchTypeTo(os.Stdout, x)
+}
+
+// SwitchTypeTo is SwitchType writing to w
+func SwitchTypeTo(w io.Writer, x interface{}) {
 	switch x.(type) {
 	case int:
-		fmt.Println("int")
+		fmt.Fprintln(w, "int")
 	case string:
-		fmt.Println("string")
-	case greeting.Salutation:
-		fmt.Println("salutation")
+		fmt.Fprintln(w, "string")
+	case core.Salutation, greeting.Salutation, goInterfaces.Salutation:
+		fmt.Fprintln(w, "salutation")
 	default:
-		fmt.Println("unknown")
+		fmt.Fprintln(w, "unknown")
 	}
 }
@@ -0,0 +1,251 @@
+package goSwitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/annicaburns/learngo/goMaps"
+)
+
+// A RuleSet chooses a prefix the same way a switch statement does, but the cases are data instead of code,
+// so they can be changed (or loaded from a config file) without recompiling.
+// Rules are checked in order and the first one whose condition matches fires - just like the cases of a switch.
+// A rule marked Fallthrough behaves like the fallthrough keyword: the next rule fires too, without checking its condition.
+// When no rule matches, Default is used - the default case.
+// Every Resolution carries a trace explaining which rules were checked and which one fired.
+// BasicRules and FallthroughRules give the same answers as SwitchBasic and SwitchFallthrough.
+
+// Subject is what the rules are matched against
+type Subject struct {
+	Name   string
+	Title  string // such as "Doctor" or "Captain"
+	Locale string
+}
+
+// Condition is the "case" part of a rule. Every field that is set must match, and a zero Condition matches everything
+type Condition struct {
+	Names     []string           // the name is one of these - like case "Mitchel", "Tom":
+	Pattern   *regexp.Regexp     // the name matches this regular expression
+	Title     string             // the title is this, ignoring case
+	Locale    string             // the locale is this or a more specific one - "es" matches "es-MX"
+	Predicate func(Subject) bool // a custom test
+}
+
+// Rule is one case of the rule set
+type Rule struct {
+	Name        string // used in the trace
+	When        Condition
+	Prefix      string
+	Fallthrough bool
+}
+
+// RuleSet is an ordered list of rules plus the default prefix
+type RuleSet struct {
+	Rules   []Rule
+	Default string
+}
+
+// TraceStep records what happened to one rule while resolving
+type TraceStep struct {
+	Rule   string
+	Fired  bool
+	Reason string
+}
+
+// Resolution is the result of resolving a prefix
+type Resolution struct {
+	Prefix string
+	Rule   string // the rule that set the prefix, or "default"
+	Trace  []TraceStep
+}
+
+// Explain describes the resolution one rule per line
+func (resolution Resolution) Explain() string {
+	var explanation strings.Builder
+	for _, step := range resolution.Trace {
+		mark := " "
+		if step.Fired {
+			mark = "*"
+		}
+		fmt.Fprintf(&explanation, "%s %-12s %s\n", mark, step.Rule, step.Reason)
+	}
+	fmt.Fprintf(&explanation, "= %q from %s\n", resolution.Prefix, resolution.Rule)
+	return explanation.String()
+}
+
+// BasicRules resolves prefixes the same way SwitchBasic does
+var BasicRules = RuleSet{
+	Rules: []Rule{
+		{Name: "Annica", When: Condition{Names: []string{"Annica"}}, Prefix: "Ms"},
+		{Name: "Mitchel", When: Condition{Names: []string{"Mitchel"}}, Prefix: "Mr"},
+	},
+	Default: "Dude",
+}
+
+// FallthroughRules resolves prefixes the same way SwitchFallthrough does - Annica falls through to "Mr"
+var FallthroughRules = RuleSet{
+	Rules: []Rule{
+		{Name: "Annica", When: Condition{Names: []string{"Annica"}}, Prefix: "Ms", Fallthrough: true},
+		{Name: "Mitchel/Tom", When: Condition{Names: []string{"Mitchel", "Tom"}}, Prefix: "Mr"},
+	},
+	Default: "Dude",
+}
+
+// matches reports whether the condition matches the subject, and why not when it doesn't
+func (condition Condition) matches(subject Subject) (matched bool, reason string) {
+	if len(condition.Names) > 0 {
+		found := false
+		for _, name := range condition.Names {
+			found = found || name == subject.Name
+		}
+		if !found {
+			return false, fmt.Sprintf("name %q is not one of %q", subject.Name, condition.Names)
+		}
+	}
+	if condition.Pattern != nil && !condition.Pattern.MatchString(subject.Name) {
+		return false, fmt.Sprintf("name %q does not match /%s/", subject.Name, condition.Pattern)
+	}
+	if condition.Title != "" && !strings.EqualFold(condition.Title, subject.Title) {
+		return false, fmt.Sprintf("title %q is not %q", subject.Title, condition.Title)
+	}
+	if condition.Locale != "" && !localeMatches(condition.Locale, subject.Locale) {
+		return false, fmt.Sprintf("locale %q is not %q", subject.Locale, condition.Locale)
+	}
+	if condition.Predicate != nil && !condition.Predicate(subject) {
+		return false, "predicate returned false"
+	}
+	return true, "matched"
+}
+
+func localeMatches(want, got string) bool {
+	want = strings.ToLower(strings.ReplaceAll(want, "_", "-"))
+	got = strings.ToLower(strings.ReplaceAll(got, "_", "-"))
+	return got == want || strings.HasPrefix(got, want+"-")
+}
+
+// Resolve runs the rules against subject
+func (ruleSet RuleSet) Resolve(subject Subject) (resolution Resolution) {
+	falling := false
+	for i, rule := range ruleSet.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		if falling {
+			resolution.Prefix, resolution.Rule = rule.Prefix, name
+			resolution.Trace = append(resolution.Trace, TraceStep{Rule: name, Fired: true, Reason: "fell through from the rule above"})
+		} else if matched, reason := rule.When.matches(subject); matched {
+			resolution.Prefix, resolution.Rule = rule.Prefix, name
+			resolution.Trace = append(resolution.Trace, TraceStep{Rule: name, Fired: true, Reason: reason})
+		} else {
+			resolution.Trace = append(resolution.Trace, TraceStep{Rule: name, Reason: reason})
+			continue
+		}
+		if falling = rule.Fallthrough; !falling {
+			return
+		}
+	}
+	if resolution.Rule == "" {
+		resolution.Prefix, resolution.Rule = ruleSet.Default, "default"
+	}
+	return
+}
+
+// Predicates that rules loaded from a config file can refer to by name
+var (
+	predicateMutex sync.RWMutex
+	predicates     = map[string]func(Subject) bool{
+		// the name has a prefix in the shared goMaps.Prefixes registry
+		"hasRegisteredPrefix": func(subject Subject) bool { return goMaps.Prefixes.Exists(subject.Name) },
+	}
+)
+
+// RegisterPredicate makes a custom predicate available to rules loaded from a config file
+func RegisterPredicate(name string, predicate func(Subject) bool) {
+	predicateMutex.Lock()
+	defer predicateMutex.Unlock()
+	predicates[name] = predicate
+}
+
+// ruleFile is the JSON layout of a rules config file
+type ruleFile struct {
+	Default string `json:"default"`
+	Rules   []struct {
+		Name        string   `json:"name"`
+		Names       []string `json:"names"`
+		Pattern     string   `json:"pattern"`
+		Title       string   `json:"title"`
+		Locale      string   `json:"locale"`
+		Predicate   string   `json:"predicate"`
+		Always      bool     `json:"always"`
+		Prefix      string   `json:"prefix"`
+		Fallthrough bool     `json:"fallthrough"`
+	} `json:"rules"`
+}
+
+// LoadRules reads a rule set from JSON such as
+//
+//	{"default": "Dude", "rules": [
+//		{"name": "Annica", "names": ["Annica"], "prefix": "Ms", "fallthrough": true},
+//		{"name": "doctors", "title": "Doctor", "prefix": "Dr"},
+//		{"name": "señoras", "locale": "es", "pattern": "a$", "prefix": "Sra"},
+//		{"name": "registered", "predicate": "hasRegisteredPrefix", "prefix": "Friend"},
+//		{"name": "everybody else", "always": true, "prefix": "Friend"}
+//	]}
+//
+// An unknown key is an error, so is a rule with no condition unless it says "always": true -
+// otherwise a misspelled condition would quietly turn the rule into one that matches everybody
+func LoadRules(r io.Reader) (ruleSet RuleSet, err error) {
+	var file ruleFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&file); err != nil {
+		return ruleSet, fmt.Errorf("goSwitch: reading rules: %w", err)
+	}
+	ruleSet.Default = file.Default
+	for i, fileRule := range file.Rules {
+		hasCondition := len(fileRule.Names) > 0 || fileRule.Pattern != "" || fileRule.Title != "" || fileRule.Locale != "" || fileRule.Predicate != ""
+		switch {
+		case !hasCondition && !fileRule.Always:
+			return RuleSet{}, fmt.Errorf("goSwitch: rule %d has no condition - add \"always\": true if it should match everybody", i+1)
+		case hasCondition && fileRule.Always:
+			return RuleSet{}, fmt.Errorf("goSwitch: rule %d says \"always\" but has a condition too", i+1)
+		}
+		rule := Rule{
+			Name:        fileRule.Name,
+			When:        Condition{Names: fileRule.Names, Title: fileRule.Title, Locale: fileRule.Locale},
+			Prefix:      fileRule.Prefix,
+			Fallthrough: fileRule.Fallthrough,
+		}
+		if fileRule.Pattern != "" {
+			if rule.When.Pattern, err = regexp.Compile(fileRule.Pattern); err != nil {
+				return RuleSet{}, fmt.Errorf("goSwitch: rule %d: %w", i+1, err)
+			}
+		}
+		if fileRule.Predicate != "" {
+			predicateMutex.RLock()
+			rule.When.Predicate = predicates[fileRule.Predicate]
+			predicateMutex.RUnlock()
+			if rule.When.Predicate == nil {
+				return RuleSet{}, fmt.Errorf("goSwitch: rule %d: unknown predicate %q", i+1, fileRule.Predicate)
+			}
+		}
+		ruleSet.Rules = append(ruleSet.Rules, rule)
+	}
+	return ruleSet, nil
+}
+
+// LoadRulesFile reads a rule set from the JSON file at path
+func LoadRulesFile(path string) (RuleSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return RuleSet{}, fmt.Errorf("goSwitch: %w", err)
+	}
+	defer f.Close()
+	return LoadRules(f)
+}
@@ -0,0 +1,147 @@
+package goSwitch
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadRules(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		ok   bool
+	}{
+		{"conditions", `{"default": "Dude", "rules": [{"names": ["Annica"], "prefix": "Ms"}, {"title": "Doctor", "prefix": "Dr"}]}`, true},
+		{"explicit catch-all", `{"rules": [{"always": true, "prefix": "Friend"}]}`, true},
+		{"misspelled condition", `{"rules": [{"nmaes": ["Annica"], "prefix": "Ms"}]}`, false},
+		{"unknown top-level key", `{"defualt": "Dude", "rules": []}`, false},
+		{"no condition", `{"rules": [{"name": "oops", "prefix": "Ms"}]}`, false},
+		{"empty names", `{"rules": [{"names": [], "prefix": "Ms"}]}`, false},
+		{"always with a condition", `{"rules": [{"always": true, "title": "Doctor", "prefix": "Dr"}]}`, false},
+		{"bad pattern", `{"rules": [{"pattern": "(", "prefix": "Ms"}]}`, false},
+		{"unknown predicate", `{"rules": [{"predicate": "nope", "prefix": "Ms"}]}`, false},
+	}
+	for _, test := range tests {
+		_, err := LoadRules(strings.NewReader(test.json))
+		if (err == nil) != test.ok {
+			t.Errorf("%s: LoadRules = %v, want ok %v", test.name, err, test.ok)
+		}
+	}
+}
+
+func TestLoadedRulesResolve(t *testing.T) {
+	rules, err := LoadRules(strings.NewReader(`{"default": "Dude", "rules": [
+		{"name": "Annica", "names": ["Annica"], "prefix": "Ms"},
+		{"name": "doctors", "title": "doctor", "prefix": "Dr"},
+		{"name": "everybody else", "always": true, "prefix": "Friend"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		subject Subject
+		prefix  string
+		rule    string
+	}{
+		{Subject{Name: "Annica"}, "Ms", "Annica"},
+		{Subject{Name: "Bob", Title: "Doctor"}, "Dr", "doctors"},
+		{Subject{Name: "Bob"}, "Friend", "everybody else"},
+	}
+	for _, test := range tests {
+		resolution := rules.Resolve(test.subject)
+		if resolution.Prefix != test.prefix || resolution.Rule != test.rule {
+			t.Errorf("Resolve(%+v) = %q from %s, want %q from %s", test.subject, resolution.Prefix, resolution.Rule, test.prefix, test.rule)
+		}
+	}
+}
+
+// steps turns a trace into "*rule" for a rule that fired and "rule" for one that was checked and skipped
+func steps(trace []TraceStep) (names []string) {
+	for _, step := range trace {
+		if step.Fired {
+			names = append(names, "*"+step.Rule)
+		} else {
+			names = append(names, step.Rule)
+		}
+	}
+	return
+}
+
+func TestFallthrough(t *testing.T) {
+	rules := RuleSet{
+		Rules: []Rule{
+			{Name: "doctors", When: Condition{Title: "doctor"}, Prefix: "Dr", Fallthrough: true},
+			{Name: "Bob", When: Condition{Names: []string{"Bob"}}, Prefix: "Mr"},
+			{Name: "everybody", Prefix: "Friend"},
+		},
+		Default: "Dude",
+	}
+	tests := []struct {
+		subject Subject
+		prefix  string
+		rule    string
+		trace   []string
+	}{
+		// doctors falls through to Bob, which fires without its condition being checked, and stops there
+		{Subject{Name: "Annica", Title: "Doctor"}, "Mr", "Bob", []string{"*doctors", "*Bob"}},
+		// Bob doesn't fall through, so everybody is never reached even though it would match
+		{Subject{Name: "Bob"}, "Mr", "Bob", []string{"doctors", "*Bob"}},
+		{Subject{Name: "Annica"}, "Friend", "everybody", []string{"doctors", "Bob", "*everybody"}},
+	}
+	for _, test := range tests {
+		resolution := rules.Resolve(test.subject)
+		if resolution.Prefix != test.prefix || resolution.Rule != test.rule {
+			t.Errorf("Resolve(%+v) = %q from %s, want %q from %s", test.subject, resolution.Prefix, resolution.Rule, test.prefix, test.rule)
+		}
+		if got := steps(resolution.Trace); !slices.Equal(got, test.trace) {
+			t.Errorf("Resolve(%+v) traced %q, want %q", test.subject, got, test.trace)
+		}
+	}
+	// falling through from the last rule has nowhere to go - the last rule's prefix stands, not the default
+	last := RuleSet{Rules: []Rule{{When: Condition{Names: []string{"Cara"}}, Prefix: "Ms", Fallthrough: true}}, Default: "Dude"}
+	if resolution := last.Resolve(Subject{Name: "Cara"}); resolution.Prefix != "Ms" || resolution.Rule != "rule 1" {
+		t.Errorf("falling through the last rule gave %q from %s", resolution.Prefix, resolution.Rule)
+	}
+}
+
+func TestRuleSetsMatchTheSwitches(t *testing.T) {
+	for _, name := range []string{"Annica", "Mitchel", "Tom", "Bob", ""} {
+		if got, want := BasicRules.Resolve(Subject{Name: name}).Prefix, SwitchBasic(name); got != want {
+			t.Errorf("BasicRules gave %s %q, SwitchBasic %q", name, got, want)
+		}
+		if got, want := FallthroughRules.Resolve(Subject{Name: name}).Prefix, SwitchFallthrough(name); got != want {
+			t.Errorf("FallthroughRules gave %s %q, SwitchFallthrough %q", name, got, want)
+		}
+	}
+}
+
+func TestTrace(t *testing.T) {
+	rules := RuleSet{
+		Rules: []Rule{
+			{Name: "Annica", When: Condition{Names: []string{"Annica"}}, Prefix: "Ms"},
+			{Name: "M names", When: Condition{Pattern: regexp.MustCompile("^M")}, Prefix: "Mx"},
+			{Name: "Spanish", When: Condition{Locale: "es"}, Prefix: "Sr"},
+		},
+		Default: "Dude",
+	}
+	resolution := rules.Resolve(Subject{Name: "Bob", Locale: "es-MX"})
+	want := []TraceStep{
+		{Rule: "Annica", Reason: `name "Bob" is not one of ["Annica"]`},
+		{Rule: "M names", Reason: `name "Bob" does not match /^M/`},
+		{Rule: "Spanish", Fired: true, Reason: "matched"},
+	}
+	if !slices.Equal(resolution.Trace, want) {
+		t.Errorf("traced %+v, want %+v", resolution.Trace, want)
+	}
+	explanation := resolution.Explain()
+	if !strings.Contains(explanation, "* Spanish") || !strings.Contains(explanation, "  Annica") || !strings.HasSuffix(explanation, "= \"Sr\" from Spanish\n") {
+		t.Errorf("Explain =\n%s", explanation)
+	}
+	// nothing matched, so every rule was skipped and the default named
+	resolution = rules.Resolve(Subject{Name: "Bob", Locale: "fr"})
+	if got := steps(resolution.Trace); !slices.Equal(got, []string{"Annica", "M names", "Spanish"}) || resolution.Rule != "default" || resolution.Prefix != "Dude" {
+		t.Errorf("with no match traced %q and chose %q from %s", got, resolution.Prefix, resolution.Rule)
+	}
+}
@@ -0,0 +1,307 @@
+package greeting
+
+import (
+	"bufio"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Catalog holds the greeting messages for each locale ("en", "es", "es-MX"...) and formality level.
+// Messages are small templates - {name}, {greeting}, {prefix} and {count} are replaced when a message is rendered.
+// Each message has two forms: One for greeting a single person and Other for greeting a group (pluralisation).
+// Lookups follow a fallback chain from the most to the least specific locale, ending at FallbackLocale: es-MX -> es -> en
+// The catalog files in the messages directory are embedded into the program and loaded into DefaultCatalog.
+
+// Formality is how formal a greeting should be
+type Formality int
+
+// The formality levels, from least to most formal
+const (
+	Casual Formality = iota
+	Neutral
+	Formal
+	Honorific
+)
+
+var formalityNames = []string{"casual", "neutral", "formal", "honorific"}
+
+func (formality Formality) String() string {
+	if formality < Casual || formality > Honorific {
+		return "Formality(" + strconv.Itoa(int(formality)) + ")"
+	}
+	return formalityNames[formality]
+}
+
+// ParseFormality turns "casual", "neutral", "formal" or "honorific" into a Formality
+func ParseFormality(name string) (Formality, error) {
+	for i, formalityName := range formalityNames {
+		if strings.EqualFold(name, formalityName) {
+			return Formality(i), nil
+		}
+	}
+	return Casual, fmt.Errorf("greeting: unknown formality %q", name)
+}
+
+// FallbackLocale is the last locale tried for every lookup
+const FallbackLocale = "en"
+
+// ErrNoMessage is returned when no locale in the fallback chain has a message for the requested formality
+var ErrNoMessage = errors.New("greeting: no message in catalog")
+
+// Message is one catalog entry
+type Message struct {
+	One   string `json:"one"`
+	Other string `json:"other"`
+}
+
+// Catalog is a set of messages keyed by locale and formality. It is safe to use from several goroutines
+type Catalog struct {
+	mutex    sync.RWMutex
+	messages map[string]map[Formality]Message
+}
+
+// NewCatalog creates an empty catalog
+func NewCatalog() *Catalog {
+	return &Catalog{messages: make(map[string]map[Formality]Message)}
+}
+
+//go:embed messages
+var embeddedMessages embed.FS
+
+// DefaultCatalog is loaded from the embedded messages directory and is used by Greet and IfGreet
+var DefaultCatalog = mustLoadCatalog(embeddedMessages, "messages")
+
+func mustLoadCatalog(fsys fs.FS, dir string) *Catalog {
+	catalog := NewCatalog()
+	if err := catalog.LoadDir(fsys, dir); err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// normalizeLocale makes "es_MX" and "ES-mx" the same key
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// FallbackChain returns the locales tried for a lookup, most specific first - "es-MX" gives [es-mx es en]
+func FallbackChain(locale string) (chain []string) {
+	locale = normalizeLocale(locale)
+	for locale != "" {
+		chain = append(chain, locale)
+		if i := strings.LastIndex(locale, "-"); i >= 0 {
+			locale = locale[:i]
+		} else {
+			locale = ""
+		}
+	}
+	if len(chain) == 0 || chain[len(chain)-1] != FallbackLocale {
+		chain = append(chain, FallbackLocale)
+	}
+	return
+}
+
+// Add puts a single message into the catalog, replacing any message already there
+func (catalog *Catalog) Add(locale string, formality Formality, message Message) {
+	catalog.mutex.Lock()
+	defer catalog.mutex.Unlock()
+	locale = normalizeLocale(locale)
+	if catalog.messages[locale] == nil {
+		catalog.messages[locale] = make(map[Formality]Message)
+	}
+	if message.Other == "" {
+		message.Other = message.One
+	}
+	catalog.messages[locale][formality] = message
+}
+
+// Lookup finds the message for a formality, walking the fallback chain. It also returns the locale the message came from
+func (catalog *Catalog) Lookup(locale string, formality Formality) (message Message, found string, err error) {
+	catalog.mutex.RLock()
+	defer catalog.mutex.RUnlock()
+	for _, candidate := range FallbackChain(locale) {
+		if message, exists := catalog.messages[candidate][formality]; exists {
+			return message, candidate, nil
+		}
+	}
+	return Message{}, "", fmt.Errorf("%w for %s %s", ErrNoMessage, locale, formality)
+}
+
+// Locales returns every locale the catalog has at least one message for
+func (catalog *Catalog) Locales() (locales []string) {
+	catalog.mutex.RLock()
+	defer catalog.mutex.RUnlock()
+	for locale := range catalog.messages {
+		locales = append(locales, locale)
+	}
+	return
+}
+
+// Values fill in a message's placeholders
+type Values struct {
+	Name     string
+	Greeting string
+	Prefix   string
+}
+
+// Render looks up a message and fills in its placeholders.
+// count picks the form - One when it is 1, Other for anything else - and is available as {count}
+func (catalog *Catalog) Render(locale string, formality Formality, count int, values Values) (string, error) {
+	message, _, err := catalog.Lookup(locale, formality)
+	if err != nil {
+		return "", err
+	}
+	text := message.Other
+	if count == 1 {
+		text = message.One
+	}
+	replacer := strings.NewReplacer(
+		"{name}", values.Name,
+		"{greeting}", values.Greeting,
+		"{prefix}", values.Prefix,
+		"{count}", strconv.Itoa(count),
+	)
+	// Collapse the double space an empty {prefix} leaves behind
+	return strings.Join(strings.Fields(replacer.Replace(text)), " "), nil
+}
+
+// DefaultGreeting is what MessageFor greets formally with when the salutation has no greeting of its own
+const DefaultGreeting = "Hello"
+
+// MessageFor renders a greeting from exactly what it is given, for services whose callers say what they want.
+// Greet always says "Hey" and IfGreet takes the prefix from the shared registry - MessageFor doesn't:
+//   - casually it is the salutation's own greeting - "Howdy, Annica" - or the locale's casual message if it has none
+//   - formally it is the locale's formal message with prefix - "Buenos días, Dr Bob" - or its neutral message
+//     if prefix is empty, greeting with DefaultGreeting if the salutation has no greeting
+func MessageFor(salutation Salutation, prefix string, isFormal bool) (string, error) {
+	values := Values{Name: salutation.Name, Greeting: salutation.Greeting, Prefix: strings.TrimSpace(prefix)}
+	switch {
+	case !isFormal && values.Greeting != "":
+		return values.Greeting + ", " + values.Name, nil
+	case !isFormal:
+		return DefaultCatalog.Render(salutation.Locale, Casual, 1, values)
+	}
+	if values.Greeting == "" {
+		values.Greeting = DefaultGreeting
+	}
+	formality := Formal
+	if values.Prefix == "" {
+		formality = Neutral
+	}
+	return DefaultCatalog.Render(salutation.Locale, formality, 1, values)
+}
+
+// catalogFile is the layout of a single catalog file, whichever format it is written in
+type catalogFile struct {
+	Locale   string             `json:"locale"`
+	Messages map[string]Message `json:"messages"`
+}
+
+func (catalog *Catalog) addFile(file catalogFile) error {
+	if file.Locale == "" {
+		return errors.New("greeting: catalog file has no locale")
+	}
+	for name, message := range file.Messages {
+		formality, err := ParseFormality(name)
+		if err != nil {
+			return err
+		}
+		catalog.Add(file.Locale, formality, message)
+	}
+	return nil
+}
+
+// LoadJSON adds the messages from a JSON catalog file
+func (catalog *Catalog) LoadJSON(r io.Reader) error {
+	var file catalogFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return fmt.Errorf("greeting: reading JSON catalog: %w", err)
+	}
+	return catalog.addFile(file)
+}
+
+// LoadTOML adds the messages from a TOML catalog file.
+// Only the small part of TOML the catalog needs is understood: comments, [messages.<formality>] tables and key = "string" pairs
+func (catalog *Catalog) LoadTOML(r io.Reader) error {
+	file := catalogFile{Messages: make(map[string]Message)}
+	table := ""
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			table = strings.TrimSpace(text[1 : len(text)-1])
+			if !strings.HasPrefix(table, "messages.") {
+				return fmt.Errorf("greeting: TOML line %d: unknown table [%s]", line, table)
+			}
+			continue
+		}
+		key, value, found := strings.Cut(text, "=")
+		if !found {
+			return fmt.Errorf("greeting: TOML line %d: expected key = \"value\"", line)
+		}
+		key = strings.TrimSpace(key)
+		value, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("greeting: TOML line %d: value must be a quoted string", line)
+		}
+		switch formality := strings.TrimPrefix(table, "messages."); {
+		case table == "" && key == "locale":
+			file.Locale = value
+		case table != "" && key == "one":
+			message := file.Messages[formality]
+			message.One = value
+			file.Messages[formality] = message
+		case table != "" && key == "other":
+			message := file.Messages[formality]
+			message.Other = value
+			file.Messages[formality] = message
+		default:
+			return fmt.Errorf("greeting: TOML line %d: unknown key %q", line, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("greeting: reading TOML catalog: %w", err)
+	}
+	return catalog.addFile(file)
+}
+
+// LoadDir adds every .json and .toml catalog file in a directory of fsys
+func (catalog *Catalog) LoadDir(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("greeting: reading catalog directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+		var load func(io.Reader) error
+		switch path.Ext(name) {
+		case ".json":
+			load = catalog.LoadJSON
+		case ".toml":
+			load = catalog.LoadTOML
+		default:
+			continue
+		}
+		f, err := fsys.Open(name)
+		if err != nil {
+			return fmt.Errorf("greeting: opening %s: %w", name, err)
+		}
+		err = load(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
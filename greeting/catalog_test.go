@@ -0,0 +1,183 @@
+package greeting
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMessageFor(t *testing.T) {
+	tests := []struct {
+		salutation Salutation
+		prefix     string
+		isFormal   bool
+		want       string
+	}{
+		{Salutation{Name: "Annica", Greeting: "Howdy"}, "", false, "Howdy, Annica"},
+		{Salutation{Name: "Annica", Greeting: "Howdy"}, "Ms ", false, "Howdy, Annica"},
+		{Salutation{Name: "Annica"}, "", false, "Hey, Annica"},
+		{Salutation{Name: "Annica", Locale: "es-MX"}, "", false, "¿Qué onda, Annica?"},
+		{Salutation{Name: "Annica", Greeting: "Good evening"}, "Ms ", true, "Good evening, Ms Annica"},
+		{Salutation{Name: "Annica"}, "Ms ", true, "Hello, Ms Annica"},
+		{Salutation{Name: "Bob"}, "", true, "Hello, Bob"},
+		{Salutation{Name: "Bob", Locale: "es"}, "Dr", true, "Buenos días, Dr Bob"},
+		{Salutation{Name: "Bob", Locale: "es"}, "  ", true, "Buenos días, Bob"},
+	}
+	for _, test := range tests {
+		message, err := MessageFor(test.salutation, test.prefix, test.isFormal)
+		if err != nil || message != test.want {
+			t.Errorf("MessageFor(%+v, %q, %v) = %q, %v, want %q", test.salutation, test.prefix, test.isFormal, message, err, test.want)
+		}
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	tests := map[string][]string{
+		"es-MX":      {"es-mx", "es", "en"},
+		"es_mx":      {"es-mx", "es", "en"},
+		" ES ":       {"es", "en"},
+		"zh-Hant-TW": {"zh-hant-tw", "zh-hant", "zh", "en"},
+		"en-GB":      {"en-gb", "en"},
+		"en":         {"en"},
+		"":           {"en"},
+	}
+	for locale, want := range tests {
+		if got := FallbackChain(locale); !slices.Equal(got, want) {
+			t.Errorf("FallbackChain(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestLookupFollowsTheChain(t *testing.T) {
+	tests := []struct {
+		locale    string
+		formality Formality
+		found     string
+	}{
+		{"es-MX", Casual, "es-mx"},
+		{"es-MX", Formal, "es"},
+		{"es-AR", Casual, "es"},
+		{"pt-BR", Neutral, "en"},
+		{"", Honorific, "en"},
+		{"ja", Formal, "ja"},
+	}
+	for _, test := range tests {
+		if _, found, err := DefaultCatalog.Lookup(test.locale, test.formality); err != nil || found != test.found {
+			t.Errorf("Lookup(%q, %v) came from %q, %v, want %q", test.locale, test.formality, found, err, test.found)
+		}
+	}
+	if _, _, err := DefaultCatalog.Lookup("es", Formality(9)); !errors.Is(err, ErrNoMessage) {
+		t.Errorf("Lookup of an unknown formality = %v, want ErrNoMessage", err)
+	}
+	if _, _, err := NewCatalog().Lookup("en", Casual); !errors.Is(err, ErrNoMessage) {
+		t.Errorf("Lookup in an empty catalog = %v, want ErrNoMessage", err)
+	}
+	if _, err := ParseFormality("chummy"); err == nil {
+		t.Error("ParseFormality accepted chummy")
+	}
+	if formality, err := ParseFormality("HONORIFIC"); formality != Honorific || err != nil {
+		t.Errorf("ParseFormality(HONORIFIC) = %v, %v", formality, err)
+	}
+}
+
+func TestRenderPicksThePluralForm(t *testing.T) {
+	values := Values{Name: "Annica", Greeting: "Hello"}
+	tests := []struct {
+		locale string
+		count  int
+		want   string
+	}{
+		{"en", 1, "Hello, Annica"},
+		{"en", 2, "Hello, all 2 of you"},
+		{"en", 0, "Hello, all 0 of you"},
+		{"fr", 1, "Bonjour, Annica"},
+		{"fr", 5, "Bonjour à tous les 5"},
+		{"ja", 3, "皆さん、こんにちは"},
+	}
+	for _, test := range tests {
+		if got, err := DefaultCatalog.Render(test.locale, Neutral, test.count, values); err != nil || got != test.want {
+			t.Errorf("Render(%s, %d) = %q, %v, want %q", test.locale, test.count, got, err, test.want)
+		}
+	}
+	// a message without an Other form uses One for both
+	catalog := NewCatalog()
+	catalog.Add("en", Casual, Message{One: "Hi, {name}"})
+	if got, _ := catalog.Render("en", Casual, 2, values); got != "Hi, Annica" {
+		t.Errorf("Render of a message with only One = %q", got)
+	}
+	// an empty prefix doesn't leave a double space behind
+	if got, _ := DefaultCatalog.Render("en", Formal, 1, values); got != "Hello, Annica" {
+		t.Errorf("Render with no prefix = %q", got)
+	}
+}
+
+func TestLoadTOMLMatchesJSON(t *testing.T) {
+	const (
+		asJSON = `{"locale": "en-AU", "messages": {"casual": {"one": "G'day, {name}", "other": "G'day, all"}, "formal": {"one": "Good day, {prefix} {name}"}}}`
+		asTOML = `# the same messages
+locale = "en-AU"
+
+[messages.casual]
+one = "G'day, {name}"
+other = "G'day, all"
+
+[messages.formal]
+one = "Good day, {prefix} {name}"
+`
+	)
+	fromJSON, fromTOML := NewCatalog(), NewCatalog()
+	if err := fromJSON.LoadJSON(strings.NewReader(asJSON)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fromTOML.LoadTOML(strings.NewReader(asTOML)); err != nil {
+		t.Fatal(err)
+	}
+	for _, formality := range []Formality{Casual, Neutral, Formal, Honorific} {
+		jsonMessage, jsonFound, jsonErr := fromJSON.Lookup("en-AU", formality)
+		tomlMessage, tomlFound, tomlErr := fromTOML.Lookup("en-AU", formality)
+		if jsonMessage != tomlMessage || jsonFound != tomlFound || (jsonErr == nil) != (tomlErr == nil) {
+			t.Errorf("%v: JSON gave %+v from %q, %v but TOML gave %+v from %q, %v",
+				formality, jsonMessage, jsonFound, jsonErr, tomlMessage, tomlFound, tomlErr)
+		}
+	}
+	for _, bad := range []string{
+		"locale = en",
+		"[other]\none = \"x\"",
+		"locale = \"en\"\n[messages.casual]\nmany = \"x\"",
+		"locale = \"en\"\n[messages.chummy]\none = \"x\"",
+		"[messages.casual]\none = \"x\"",
+		"just words",
+	} {
+		if err := NewCatalog().LoadTOML(strings.NewReader(bad)); err == nil {
+			t.Errorf("LoadTOML accepted %q", bad)
+		}
+	}
+}
+
+func TestLoadDirAndLocales(t *testing.T) {
+	fsys := fstest.MapFS{
+		"catalog/en.json":   {Data: []byte(`{"locale": "en", "messages": {"casual": {"one": "Hi, {name}"}}}`)},
+		"catalog/ja.toml":   {Data: []byte("locale = \"ja\"\n[messages.casual]\none = \"やあ\"\n")},
+		"catalog/README.md": {Data: []byte("not a catalog")},
+	}
+	catalog := NewCatalog()
+	if err := catalog.LoadDir(fsys, "catalog"); err != nil {
+		t.Fatal(err)
+	}
+	locales := catalog.Locales()
+	slices.Sort(locales)
+	if want := []string{"en", "ja"}; !slices.Equal(locales, want) {
+		t.Errorf("Locales = %q, want %q", locales, want)
+	}
+	locales = DefaultCatalog.Locales()
+	slices.Sort(locales)
+	if want := []string{"de", "en", "es", "es-mx", "fr", "ja"}; !slices.Equal(locales, want) {
+		t.Errorf("the embedded catalog has %q, want %q", locales, want)
+	}
+	fsys["catalog/broken.json"] = &fstest.MapFile{Data: []byte("{")}
+	if err := NewCatalog().LoadDir(fsys, "catalog"); err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("LoadDir with a broken file = %v", err)
+	}
+}
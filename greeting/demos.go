@@ -0,0 +1,135 @@
+package greeting
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/annicaburns/learngo/registry"
+)
+
+// Parameters only the greeting demos use
+var (
+	localeParam    = registry.Param{Name: "locale", Kind: registry.String, Default: FallbackLocale, Description: "locale of the message, such as es-MX"}
+	formalityParam = registry.Param{Name: "formality", Kind: registry.String, Default: "neutral", Description: "casual, neutral, formal or honorific"}
+	prefixParam    = registry.Param{Name: "prefix", Kind: registry.String, Default: "", Description: "honorific used by formal messages - empty asks the shared prefix registry"}
+	countParam     = registry.Param{Name: "count", Kind: registry.Int, Default: "1", Description: "number of people being greeted"}
+	strategyParam  = registry.Param{Name: "strategy", Kind: registry.String, Default: "round-robin", Description: "first, last, random, round-robin, weighted or an index"}
+	greetingsParam = registry.Param{Name: "greetings", Kind: registry.String, Default: "Hi,Hello,Howdy", Description: "comma separated greetings to choose from"}
+	weightsParam   = registry.Param{Name: "weights", Kind: registry.String, Default: "", Description: "comma separated weight for each greeting, used by the weighted strategy"}
+	seedParam      = registry.Param{Name: "seed", Kind: registry.Int, Default: "1", Description: "seed for the random and weighted strategies"}
+	templateParam  = registry.Param{Name: "template", Kind: registry.String, Default: `{{.Greeting}}, {{title .Name}}{{if .Formal}}!{{end}}`, Description: "text/template used for the message"}
+)
+
+// Register the greeting demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "greeting.Greet",
+		Description: "greet by passing a function literal",
+		Params:      []registry.Param{registry.NameParam, registry.GreetingParam, localeParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			Greet(Salutation{Name: args.String("name"), Greeting: args.String("greeting"), Locale: args.String("locale")}, LinePrinter(w))
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "greeting.CatalogGreet",
+		Description: "render a message from the locale-aware catalog",
+		Params:      []registry.Param{registry.NameParam, registry.GreetingParam, prefixParam, localeParam, formalityParam, countParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			formality, err := ParseFormality(args.String("formality"))
+			if err != nil {
+				return err
+			}
+			values := Values{Name: args.String("name"), Greeting: args.String("greeting"), Prefix: args.String("prefix")}
+			if values.Prefix == "" {
+				values.Prefix = Salutation{Name: values.Name}.templateData(true).Prefix
+			}
+			message, err := DefaultCatalog.Render(args.String("locale"), formality, args.Int("count"), values)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(w, message)
+			return err
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "greeting.IfGreet",
+		Description: "greet formally or casually with an if statement",
+		Params:      []registry.Param{registry.NameParam, registry.GreetingParam, localeParam, registry.IsFormalParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			IfGreet(Salutation{Name: args.String("name"), Greeting: args.String("greeting"), Locale: args.String("locale")}, LinePrinter(w), args.Bool("isFormal"))
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "greeting.TemplateGreet",
+		Description: "greet with your own text/template message",
+		Params:      []registry.Param{registry.NameParam, registry.GreetingParam, localeParam, registry.IsFormalParam, templateParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			templates := NewTemplates()
+			if err := templates.Add("custom", args.String("template")); err != nil {
+				return err
+			}
+			salutation := Salutation{Name: args.String("name"), Greeting: args.String("greeting"), Locale: args.String("locale")}
+			return GreetWith(templates, "custom", salutation, LinePrinter(w), args.Bool("isFormal"))
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "greeting.PrintVariadicGreet",
+		Description: "call a variadic function, and see it report too few arguments",
+		Run: func(w io.Writer, args registry.Args) error {
+			PrintVariadicGreetTo(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "greeting.PickGreeting",
+		Description: "choose from variadic greetings with a strategy",
+		Params:      []registry.Param{registry.NameParam, strategyParam, greetingsParam, weightsParam, seedParam, registry.TimesParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			var weights []float64
+			for _, text := range splitList(args.String("weights")) {
+				weight, err := strconv.ParseFloat(text, 64)
+				if err != nil {
+					return fmt.Errorf("%w: weights must be numbers, got %q", registry.ErrInvalidParam, text)
+				}
+				weights = append(weights, weight)
+			}
+			strategy, err := ParseStrategy(args.String("strategy"), uint64(args.Int("seed")), weights...)
+			if err != nil {
+				return fmt.Errorf("%w: %v", registry.ErrInvalidParam, err)
+			}
+			greetings := splitList(args.String("greetings"))
+			for i := 0; i < args.Int("times"); i++ {
+				greeting, err := PickGreeting(args.String("name"), strategy, greetings...)
+				if err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(w, "%s, %s\n", greeting, args.String("name")); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	registry.Register(registry.Demo{
+		Name:        "greeting.PointerExample",
+		Description: "share a value through a pointer",
+		Run: func(w io.Writer, args registry.Args) error {
+			PointerExampleTo(w)
+			return nil
+		},
+	})
+}
+
+// splitList splits a comma separated parameter, leaving out empty items
+func splitList(list string) (items []string) {
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
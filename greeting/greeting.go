@@ -2,12 +2,16 @@ package greeting
 
 import (
 	"fmt"
+	"io"
+	"os"
 )
 
 // Capitalize the name "Salutation" to "export" it (make it visible) outside of this package
+// The struct tags tell encoding/json and encoding/xml what to call each field
 type Salutation struct {
-	Name     string
-	Greeting string
+	Name     string `json:"name" xml:"name,attr"`
+	Greeting string `json:"greeting" xml:"greeting"`
+	Locale   string `json:"locale,omitempty" xml:"locale,attr,omitempty"` // picks the messages used from DefaultCatalog - empty means FallbackLocale
 }
 
 type printer func(string)
@@ -28,24 +32,36 @@ const (
 )
 
 // Return multiple values - tuple. Name the return values to assign them at different times
-func createMessage(name, greeting string) (message string, alternate string) {
-	message = greeting + ", " + name
-	alternate = "Hey, " + name
+// The messages are rendered by the MessageTemplate and AlternateTemplate in DefaultTemplates.
+// If a template has been replaced with one that fails, fall back to plain concatenation so there is always something to print
+func createMessage(salutation Salutation) (message string, alternate string) {
+	data := salutation.templateData(false)
+	message, err := DefaultTemplates.Render(MessageTemplate, data)
+	if err != nil {
+		message = salutation.Greeting + ", " + salutation.Name
+	}
+	alternate, err = DefaultTemplates.Render(AlternateTemplate, data)
+	if err != nil {
+		alternate = "Hey, " + salutation.Name
+	}
 	return
 }
 
 // Use an underscore to ignore one of the return values
 // Example of a function type being passed as an argument
 func Greet(salutation Salutation, passedFunctionLiteral printer) {
-	_, alternate := createMessage(salutation.Name, salutation.Greeting)
+	_, alternate := createMessage(salutation)
 	passedFunctionLiteral(alternate)
 }
 
 // If statement example - using the embedded statement format of the if statement
 func IfGreet(salutation Salutation, passedFunctionLiteral printer, isFormal bool) {
-	message, alternate := createMessage(salutation.Name, salutation.Greeting)
-	if extraSugar := " (sweetheart)"; isFormal {
-		passedFunctionLiteral(message + extraSugar)
+	message, alternate := createMessage(salutation)
+	if formal, err := DefaultTemplates.Render(FormalTemplate, salutation.templateData(true)); isFormal {
+		if err != nil {
+			formal = message
+		}
+		passedFunctionLiteral(formal)
 	} else {
 		passedFunctionLiteral(alternate)
 	}
@@ -58,7 +74,7 @@ func createPrintFunction(custom string) printer {
 
 // Example of a using a Closure
 func useClosure() {
-	var sal = Salutation{"Annica", "Dearest"}
+	var sal = Salutation{Name: "Annica", Greeting: "Dearest"}
 	Greet(sal, createPrintFunction("000"))
 }
 
@@ -70,21 +86,39 @@ func printLine(s string) {
 	fmt.Println(s)
 }
 
+// LinePrinter returns a function literal that can be passed to Greet and IfGreet to print each message on its own line to w
+func LinePrinter(w io.Writer) func(string) {
+	return func(s string) { fmt.Fprintln(w, s) }
+}
+
 // Variadic functions - a variable number of parameters of a certain type - has to come as the last parameter
-func variadicMessage(name string, greeting ...string) (result string) {
-	result = greeting[2]
-	return
+// Inside the function greeting is a []string, which might be empty - so check before indexing into it, or let PickGreeting do it
+func variadicMessage(name string, greeting ...string) (result string, err error) {
+	return PickGreeting(name, Index(2), greeting...)
 }
 
-func variadicGreet(salutation Salutation) {
-	result := variadicMessage(salutation.Name, salutation.Greeting, "greeting1", "greeting2")
-	println("result: ", result)
+func variadicGreet(w io.Writer, salutation Salutation) {
+	result, err := variadicMessage(salutation.Name, salutation.Greeting, "greeting1", "greeting2")
+	if err != nil {
+		fmt.Fprintln(w, "error: ", err)
+		return
+	}
+	fmt.Fprintln(w, "result: ", result)
+	// with only one greeting there is no greeting[2] - this used to panic with index out of range
+	if _, err := variadicMessage(salutation.Name, salutation.Greeting); err != nil {
+		fmt.Fprintln(w, "error: ", err)
+	}
 }
 
 // PrintVariadicGreet demonstrates calling a variadic function
 func PrintVariadicGreet() {
-	saluation := Salutation{"Annica", "Hi"}
-	variadicGreet(saluation)
+	PrintVariadicGreetTo(os.Stdout)
+}
+
+// PrintVariadicGreetTo is PrintVariadicGreet writing to w
+func PrintVariadicGreetTo(w io.Writer) {
+	saluation := Salutation{Name: "Annica", Greeting: "Hi"}
+	variadicGreet(w, saluation)
 }
 
 func constantExample() {
@@ -96,7 +130,7 @@ func userDefinedTypeExample() {
 	// s.name = "Annica"
 	// s.greeting = "Hello"
 	// var s = Salutation{greeting: "Bye", name: "Annica"}
-	var s = Salutation{"Annica", "Hi"}
+	var s = Salutation{Name: "Annica", Greeting: "Hi"}
 
 	fmt.Println(s.Name, s.Greeting)
 
@@ -104,9 +138,14 @@ func userDefinedTypeExample() {
 
 // PointerExample demonstrates passing by reference through pointers
 func PointerExample() {
+	PointerExampleTo(os.Stdout)
+}
+
+// PointerExampleTo is PointerExample writing to w
+func PointerExampleTo(w io.Writer) {
 	message := "Hello, little chickies"
 	// &message passes a reference to the message - so it is not a copy
 	var greeting = &message
 	message = message + "!"
-	fmt.Println(message, *greeting)
+	fmt.Fprintln(w, message, *greeting)
 }
@@ -0,0 +1,195 @@
+package greeting
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Strategy chooses one greeting from a variadic list - the services call PickGreeting with the greetings
+// a person could get and a strategy saying which one they do get:
+//   First, Last    - always the same end of the list
+//   Index          - a fixed position, such as Index(2)
+//   Random         - any greeting, from a seeded generator so a run can be repeated
+//   RoundRobin     - each greeting in turn, counted separately for every name
+//   Weighted       - a random greeting, with some more likely than others
+// Random, RoundRobin and Weighted keep state between calls and are safe to use from several goroutines.
+
+// Errors returned by PickGreeting and the strategies. Use errors.Is to check for them
+var (
+	ErrNoGreetings     = errors.New("greeting: no greetings to choose from")
+	ErrIndexOutOfRange = errors.New("greeting: greeting index out of range")
+	ErrInvalidWeights  = errors.New("greeting: invalid weights")
+	ErrUnknownStrategy = errors.New("greeting: unknown strategy")
+)
+
+// Strategy chooses the index of the greeting for name from count greetings. PickGreeting never asks with a count below 1
+type Strategy interface {
+	Choose(name string, count int) (int, error)
+}
+
+// StrategyFunc lets an ordinary function be used as a Strategy
+type StrategyFunc func(name string, count int) (int, error)
+
+// Choose calls the function
+func (choose StrategyFunc) Choose(name string, count int) (int, error) {
+	return choose(name, count)
+}
+
+// PickGreeting returns the greeting strategy chooses for name - a nil strategy means First.
+// It returns ErrNoGreetings when there are no greetings and ErrIndexOutOfRange when the strategy chooses one that isn't there
+func PickGreeting(name string, strategy Strategy, greetings ...string) (string, error) {
+	if len(greetings) == 0 {
+		return "", ErrNoGreetings
+	}
+	if strategy == nil {
+		strategy = First
+	}
+	index, err := strategy.Choose(name, len(greetings))
+	if err != nil {
+		return "", err
+	}
+	if index < 0 || index >= len(greetings) {
+		return "", fmt.Errorf("%w: %d of %d greetings", ErrIndexOutOfRange, index, len(greetings))
+	}
+	return greetings[index], nil
+}
+
+// First always chooses the first greeting
+var First Strategy = StrategyFunc(func(name string, count int) (int, error) { return 0, nil })
+
+// Last always chooses the last greeting
+var Last Strategy = StrategyFunc(func(name string, count int) (int, error) { return count - 1, nil })
+
+// Index always chooses the greeting at that position, counting from 0
+type Index int
+
+// Choose returns the index, or ErrIndexOutOfRange if there aren't that many greetings
+func (index Index) Choose(name string, count int) (int, error) {
+	if int(index) < 0 || int(index) >= count {
+		return 0, fmt.Errorf("%w: %d of %d greetings", ErrIndexOutOfRange, index, count)
+	}
+	return int(index), nil
+}
+
+// Random chooses any greeting with the same chance
+type Random struct {
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+// NewRandom creates a Random strategy. The same seed chooses the same greetings in the same order
+func NewRandom(seed uint64) *Random {
+	return &Random{rand: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// Choose returns a random index below count
+func (random *Random) Choose(name string, count int) (int, error) {
+	random.mutex.Lock()
+	defer random.mutex.Unlock()
+	return random.rand.IntN(count), nil
+}
+
+// RoundRobin gives each name the greetings in turn, starting again from the first after the last.
+// It remembers every name it has seen - use Forget for names that won't be greeted again
+type RoundRobin struct {
+	mutex sync.Mutex
+	next  map[string]int
+}
+
+// NewRoundRobin creates a RoundRobin strategy that hasn't greeted anyone yet
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{next: make(map[string]int)}
+}
+
+// Choose returns the next index for name. If the list has shrunk since name was last greeted, it wraps around
+func (roundRobin *RoundRobin) Choose(name string, count int) (int, error) {
+	roundRobin.mutex.Lock()
+	defer roundRobin.mutex.Unlock()
+	index := roundRobin.next[name] % count
+	roundRobin.next[name] = (index + 1) % count
+	return index, nil
+}
+
+// Forget starts name from the first greeting again
+func (roundRobin *RoundRobin) Forget(name string) {
+	roundRobin.mutex.Lock()
+	defer roundRobin.mutex.Unlock()
+	delete(roundRobin.next, name)
+}
+
+// Weighted chooses a greeting at random, in proportion to its weight - weights of 3 and 1 choose the first greeting three times as often
+type Weighted struct {
+	mutex   sync.Mutex
+	rand    *rand.Rand
+	weights []float64
+	total   float64
+}
+
+// NewWeighted creates a Weighted strategy with a weight for each greeting.
+// Every weight must be zero or more, at least one must be more than zero, and there must be one for each greeting PickGreeting is given
+func NewWeighted(seed uint64, weights ...float64) (*Weighted, error) {
+	var total float64
+	for i, weight := range weights {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("%w: weight %d is %v", ErrInvalidWeights, i, weight)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("%w: no weight is more than zero", ErrInvalidWeights)
+	}
+	// keep a copy so the caller changing its slice can't change the odds
+	return &Weighted{rand: rand.New(rand.NewPCG(seed, seed)), weights: append([]float64(nil), weights...), total: total}, nil
+}
+
+// Choose returns a random index, or ErrInvalidWeights when count isn't the number of weights
+func (weighted *Weighted) Choose(name string, count int) (int, error) {
+	if count != len(weighted.weights) {
+		return 0, fmt.Errorf("%w: %d weights for %d greetings", ErrInvalidWeights, len(weighted.weights), count)
+	}
+	weighted.mutex.Lock()
+	point := weighted.rand.Float64() * weighted.total
+	weighted.mutex.Unlock()
+	for i, weight := range weighted.weights {
+		if point < weight {
+			return i, nil
+		}
+		point -= weight
+	}
+	// rounding can leave point just past the end - the last greeting with any weight gets it
+	for i := len(weighted.weights) - 1; ; i-- {
+		if weighted.weights[i] > 0 {
+			return i, nil
+		}
+	}
+}
+
+// ParseStrategy creates a strategy from its name: "first", "last", "random", "round-robin", "weighted" or an index such as "2".
+// seed is used by random and weighted, and weights only by weighted
+func ParseStrategy(name string, seed uint64, weights ...float64) (Strategy, error) {
+	switch strings.ToLower(name) {
+	case "first":
+		return First, nil
+	case "last":
+		return Last, nil
+	case "random":
+		return NewRandom(seed), nil
+	case "round-robin", "roundrobin":
+		return NewRoundRobin(), nil
+	case "weighted":
+		weighted, err := NewWeighted(seed, weights...)
+		if err != nil {
+			return nil, err
+		}
+		return weighted, nil
+	}
+	if index, err := strconv.Atoi(name); err == nil {
+		return Index(index), nil
+	}
+	return nil, fmt.Errorf("%w %q - use first, last, random, round-robin, weighted or an index", ErrUnknownStrategy, name)
+}
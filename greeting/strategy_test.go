@@ -0,0 +1,188 @@
+package greeting
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+var greetings = []string{"Hi", "Hey", "Howdy"}
+
+func TestPickGreeting(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  Strategy
+		greetings []string
+		want      string
+		err       error
+	}{
+		{"first", First, greetings, "Hi", nil},
+		{"last", Last, greetings, "Howdy", nil},
+		{"index", Index(1), greetings, "Hey", nil},
+		{"only one", Last, greetings[:1], "Hi", nil},
+		{"index past the end", Index(3), greetings, "", ErrIndexOutOfRange},
+		{"negative index", Index(-1), greetings, "", ErrIndexOutOfRange},
+		{"no greetings", First, nil, "", ErrNoGreetings},
+		{"no strategy", nil, greetings, "Hi", nil},
+		{"no strategy or greetings", nil, nil, "", ErrNoGreetings},
+		{"strategy out of range", StrategyFunc(func(string, int) (int, error) { return 5, nil }), greetings, "", ErrIndexOutOfRange},
+	}
+	for _, test := range tests {
+		got, err := PickGreeting("Annica", test.strategy, test.greetings...)
+		if got != test.want || !errors.Is(err, test.err) {
+			t.Errorf("%s: PickGreeting = %q, %v, want %q, %v", test.name, got, err, test.want, test.err)
+		}
+	}
+}
+
+// choices calls strategy n times for name
+func choices(t *testing.T, strategy Strategy, name string, count, n int) []int {
+	t.Helper()
+	chosen := make([]int, n)
+	for i := range chosen {
+		index, err := strategy.Choose(name, count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index < 0 || index >= count {
+			t.Fatalf("chose %d of %d", index, count)
+		}
+		chosen[i] = index
+	}
+	return chosen
+}
+
+func TestRandomRepeatsWithTheSameSeed(t *testing.T) {
+	first, again, other := choices(t, NewRandom(7), "Annica", 10, 50), choices(t, NewRandom(7), "Annica", 10, 50), choices(t, NewRandom(8), "Annica", 10, 50)
+	same, differs := true, false
+	for i := range first {
+		same = same && first[i] == again[i]
+		differs = differs || first[i] != other[i]
+	}
+	if !same {
+		t.Error("two Randoms with the same seed chose differently")
+	}
+	if !differs {
+		t.Error("two Randoms with different seeds chose the same 50 greetings")
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	roundRobin := NewRoundRobin()
+	for i, want := range []int{0, 1, 2, 0} {
+		if got := choices(t, roundRobin, "Annica", 3, 1)[0]; got != want {
+			t.Fatalf("call %d for Annica chose %d, want %d", i, got, want)
+		}
+	}
+	// every name has its own turn
+	if got := choices(t, roundRobin, "Bob", 3, 1)[0]; got != 0 {
+		t.Errorf("Bob's first greeting is %d, want 0", got)
+	}
+	// Annica is due greeting 1 of 3, which wraps to 1 of 1 - that is, 0
+	if got := choices(t, roundRobin, "Annica", 1, 1)[0]; got != 0 {
+		t.Errorf("after the list shrank Annica got %d, want 0", got)
+	}
+	choices(t, roundRobin, "Annica", 3, 2)
+	roundRobin.Forget("Annica")
+	if got := choices(t, roundRobin, "Annica", 3, 1)[0]; got != 0 {
+		t.Errorf("after Forget Annica got %d, want 0", got)
+	}
+}
+
+func TestRoundRobinFromSeveralGoroutines(t *testing.T) {
+	roundRobin := NewRoundRobin()
+	counts := make([]int, 4)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				index, _ := roundRobin.Choose("Annica", len(counts))
+				mutex.Lock()
+				counts[index]++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	// 800 turns through 4 greetings land on each one 200 times, whatever order the goroutines ran in
+	for index, count := range counts {
+		if count != 200 {
+			t.Errorf("greeting %d was chosen %d times, want 200", index, count)
+		}
+	}
+}
+
+func TestWeightedFollowsTheWeights(t *testing.T) {
+	weighted, err := NewWeighted(1, 3, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 10000
+	counts := make([]int, 3)
+	for _, index := range choices(t, weighted, "Annica", 3, n) {
+		counts[index]++
+	}
+	if counts[1] != 0 {
+		t.Errorf("a greeting with no weight was chosen %d times", counts[1])
+	}
+	if share := float64(counts[0]) / n; math.Abs(share-0.75) > 0.03 {
+		t.Errorf("a greeting with 3 of 4 weight was chosen %.1f%% of the time", share*100)
+	}
+	if _, err := weighted.Choose("Annica", 2); !errors.Is(err, ErrInvalidWeights) {
+		t.Errorf("Choose with 3 weights for 2 greetings = %v, want ErrInvalidWeights", err)
+	}
+}
+
+func TestNewWeightedRejectsBadWeights(t *testing.T) {
+	for _, weights := range [][]float64{nil, {0, 0}, {1, -1}, {math.NaN()}, {math.Inf(1)}} {
+		if _, err := NewWeighted(1, weights...); !errors.Is(err, ErrInvalidWeights) {
+			t.Errorf("NewWeighted(%v) = %v, want ErrInvalidWeights", weights, err)
+		}
+	}
+	// the strategy keeps its own copy of the weights
+	weights := []float64{1, 0}
+	weighted, _ := NewWeighted(1, weights...)
+	weights[0], weights[1] = 0, 1
+	for _, index := range choices(t, weighted, "Annica", 2, 100) {
+		if index != 0 {
+			t.Fatal("changing the caller's slice changed the weights")
+		}
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+		want    string // what PickGreeting then chooses from greetings, for the strategies that always choose the same
+		err     error
+	}{
+		{"first", nil, "Hi", nil},
+		{"LAST", nil, "Howdy", nil},
+		{"2", nil, "Howdy", nil},
+		{"round-robin", nil, "Hi", nil},
+		{"roundrobin", nil, "Hi", nil},
+		{"random", nil, "", nil},
+		{"weighted", []float64{0, 1, 0}, "Hey", nil},
+		{"weighted", nil, "", ErrInvalidWeights},
+		{"sometimes", nil, "", ErrUnknownStrategy},
+		{"", nil, "", ErrUnknownStrategy},
+	}
+	for _, test := range tests {
+		strategy, err := ParseStrategy(test.name, 1, test.weights...)
+		if !errors.Is(err, test.err) {
+			t.Errorf("ParseStrategy(%q, %v) = %v, want %v", test.name, test.weights, err, test.err)
+			continue
+		}
+		if err != nil || test.want == "" {
+			continue
+		}
+		if got, err := PickGreeting("Annica", strategy, greetings...); got != test.want || err != nil {
+			t.Errorf("ParseStrategy(%q) picked %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package greeting
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+	"unicode"
+
+	"github.com/annicaburns/learngo/goMaps"
+)
+
+// Templates are named text/template messages such as `{{.Greeting}}, {{.Prefix}} {{.Name}}{{if .Formal}}!{{end}}`
+// Every template is checked when it is added - a syntax error or a reference to a field TemplateData doesn't have
+// is reported then, rather than the first time somebody is greeted.
+// Besides the text/template builtins, templates can call:
+//   title    "annica burns" -> "Annica Burns"
+//   initials "Annica Burns" -> "A.B."
+//   ordinal  2 -> "2nd"
+//   message  renders a DefaultCatalog message for the data - {{message . "casual"}}
+
+// The templates Greet and IfGreet use. Replace them in DefaultTemplates to change how everybody is greeted
+const (
+	MessageTemplate   = "message"   // the first value returned by createMessage
+	AlternateTemplate = "alternate" // used by Greet, and by IfGreet when it isn't formal
+	FormalTemplate    = "formal"    // used by IfGreet when it is formal
+)
+
+// TemplateData is everything a template can refer to
+type TemplateData struct {
+	Name     string
+	Greeting string
+	Prefix   string
+	Locale   string
+	Formal   bool
+	Count    int
+}
+
+// Errors returned by Templates. Use errors.Is to check for them
+var (
+	ErrUnknownTemplate = errors.New("greeting: unknown template")
+	ErrInvalidTemplate = errors.New("greeting: invalid template")
+)
+
+// Templates is a set of named message templates. It is safe to use from several goroutines
+type Templates struct {
+	mutex     sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// NewTemplates creates an empty set of templates
+func NewTemplates() *Templates {
+	return &Templates{templates: make(map[string]*template.Template)}
+}
+
+// DefaultTemplates produce the messages Greet and IfGreet always have, without the " (sweetheart)"
+// IfGreet used to add to its formal message - that was never right in every locale. The formal message
+// puts the prefix from goMaps.Prefixes in front of the name - "Dearest, Ms Annica" - and reads like the
+// neutral one for a name without a prefix
+var DefaultTemplates = mustTemplates(map[string]string{
+	MessageTemplate:   `{{message . "neutral"}}`,
+	AlternateTemplate: `{{message . "casual"}}`,
+	FormalTemplate:    `{{message . "formal"}}`,
+})
+
+func mustTemplates(texts map[string]string) *Templates {
+	templates := NewTemplates()
+	for name, text := range texts {
+		if err := templates.Add(name, text); err != nil {
+			panic(err)
+		}
+	}
+	return templates
+}
+
+var templateFuncs = template.FuncMap{
+	"title":    titleCase,
+	"initials": initials,
+	"ordinal":  ordinal,
+	"message": func(data TemplateData, formality string) (string, error) {
+		level, err := ParseFormality(formality)
+		if err != nil {
+			return "", err
+		}
+		return DefaultCatalog.Render(data.Locale, level, data.Count, Values{Name: data.Name, Greeting: data.Greeting, Prefix: data.Prefix})
+	},
+}
+
+// sampleData is used to try out every template as it is added, formal and not, so both sides of
+// an {{if .Formal}} are run
+var sampleData = []TemplateData{
+	{Name: "Annica Burns", Greeting: "Hello", Prefix: "Ms", Locale: FallbackLocale, Formal: true, Count: 1},
+	{Name: "Annica Burns", Greeting: "Hello", Prefix: "Ms", Locale: FallbackLocale, Formal: false, Count: 2},
+}
+
+// templateFields are the fields of TemplateData, which are all a template can refer to
+var templateFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	dataType := reflect.TypeOf(TemplateData{})
+	for i := 0; i < dataType.NumField(); i++ {
+		fields[dataType.Field(i).Name] = true
+	}
+	return fields
+}()
+
+// Add parses and validates a template, replacing any template already using that name.
+// Every field the template names is checked, even in a branch the sample data doesn't reach
+func (templates *Templates) Add(name, text string) error {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidTemplate, name, err)
+	}
+	if err := checkFields(tmpl.Tree.Root); err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidTemplate, name, err)
+	}
+	for _, data := range sampleData {
+		var discard strings.Builder
+		if err := tmpl.Execute(&discard, data); err != nil {
+			return fmt.Errorf("%w %q: %v", ErrInvalidTemplate, name, explainExecError(err))
+		}
+	}
+	templates.mutex.Lock()
+	defer templates.mutex.Unlock()
+	templates.templates[name] = tmpl
+	return nil
+}
+
+// Names returns the name of every template in alphabetical order
+func (templates *Templates) Names() (names []string) {
+	templates.mutex.RLock()
+	defer templates.mutex.RUnlock()
+	for name := range templates.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Render executes the named template
+func (templates *Templates) Render(name string, data TemplateData) (string, error) {
+	templates.mutex.RLock()
+	tmpl, exists := templates.templates[name]
+	templates.mutex.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+	var message strings.Builder
+	if err := tmpl.Execute(&message, data); err != nil {
+		return "", fmt.Errorf("greeting: rendering template %q: %v", name, explainExecError(err))
+	}
+	return message.String(), nil
+}
+
+// checkFields walks a parsed template and reports the first field that TemplateData doesn't have.
+// TemplateData's fields are strings, bools and ints, which have no fields of their own, so .Name.First is wrong too
+func checkFields(node parse.Node) error {
+	var idents []string
+	switch node := node.(type) {
+	case nil:
+		return nil
+	case *parse.FieldNode:
+		idents = node.Ident
+	case *parse.VariableNode:
+		if node.Ident[0] != "$" {
+			return nil // a variable set inside the template could hold anything
+		}
+		idents = node.Ident[1:]
+	case *parse.ChainNode:
+		if len(node.Field) > 0 {
+			return fmt.Errorf("unknown field .%s - templates can use .Name, .Greeting, .Prefix, .Locale, .Formal and .Count", node.Field[0])
+		}
+		return checkFields(node.Node)
+	case *parse.ListNode:
+		if node == nil {
+			return nil
+		}
+		for _, child := range node.Nodes {
+			if err := checkFields(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.ActionNode:
+		return checkFields(node.Pipe)
+	case *parse.PipeNode:
+		if node == nil {
+			return nil
+		}
+		for _, command := range node.Cmds {
+			if err := checkFields(command); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			if err := checkFields(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.IfNode:
+		return checkBranch(&node.BranchNode)
+	case *parse.RangeNode:
+		return checkBranch(&node.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(&node.BranchNode)
+	case *parse.TemplateNode:
+		return checkFields(node.Pipe)
+	default:
+		return nil
+	}
+	if len(idents) == 0 {
+		return nil
+	}
+	if !templateFields[idents[0]] {
+		return fmt.Errorf("unknown field .%s - templates can use .Name, .Greeting, .Prefix, .Locale, .Formal and .Count", idents[0])
+	}
+	if len(idents) > 1 {
+		return fmt.Errorf("unknown field .%s.%s - .%s has no fields", idents[0], idents[1], idents[0])
+	}
+	return nil
+}
+
+func checkBranch(branch *parse.BranchNode) error {
+	for _, node := range []parse.Node{branch.Pipe, branch.List, branch.ElseList} {
+		if err := checkFields(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var unknownField = regexp.MustCompile(`can't evaluate field (\w+)`)
+
+// explainExecError turns text/template's "can't evaluate field" into something that says which fields do exist
+func explainExecError(err error) error {
+	if match := unknownField.FindStringSubmatch(err.Error()); match != nil {
+		return fmt.Errorf("unknown field .%s - templates can use .Name, .Greeting, .Prefix, .Locale, .Formal and .Count", match[1])
+	}
+	return err
+}
+
+// templateData builds the data for the salutation's templates. The prefix comes from the shared goMaps.Prefixes registry
+func (salutation Salutation) templateData(isFormal bool) TemplateData {
+	prefix, _ := goMaps.Prefixes.Lookup(salutation.Name)
+	return TemplateData{
+		Name:     salutation.Name,
+		Greeting: salutation.Greeting,
+		Prefix:   strings.TrimSpace(prefix),
+		Locale:   salutation.Locale,
+		Formal:   isFormal,
+		Count:    1,
+	}
+}
+
+// GreetWith greets using a named template from templates, reporting any problem rather than falling back to a default
+func GreetWith(templates *Templates, name string, salutation Salutation, passedFunctionLiteral printer, isFormal bool) error {
+	message, err := templates.Render(name, salutation.templateData(isFormal))
+	if err != nil {
+		return err
+	}
+	passedFunctionLiteral(message)
+	return nil
+}
+
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+func initials(s string) string {
+	var result strings.Builder
+	for _, word := range strings.Fields(s) {
+		result.WriteRune(unicode.ToUpper([]rune(word)[0]))
+		result.WriteByte('.')
+	}
+	return result.String()
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 100 {
+	case 11, 12, 13:
+	default:
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
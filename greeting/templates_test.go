@@ -0,0 +1,75 @@
+package greeting
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/annicaburns/learngo/goMaps"
+)
+
+func TestDefaultFormalTemplateHasNoSuffix(t *testing.T) {
+	for _, locale := range []string{FallbackLocale, "es"} {
+		message, err := DefaultTemplates.Render(FormalTemplate, TemplateData{Name: "Bob", Greeting: "Hello", Locale: locale, Formal: true, Count: 1})
+		if err != nil {
+			t.Fatalf("locale %s: %v", locale, err)
+		}
+		if strings.Contains(message, "sweetheart") {
+			t.Errorf("locale %s: formal message %q still has the suffix", locale, message)
+		}
+	}
+}
+
+func TestIfGreetUsesTheRegisteredPrefix(t *testing.T) {
+	if prefix, _ := goMaps.Prefixes.Lookup("Annica"); prefix != "Ms " {
+		t.Fatalf("the shared registry gives Annica %q - the test expects Ms", prefix)
+	}
+	if goMaps.Prefixes.Exists("Bob") {
+		t.Fatal("the shared registry has a prefix for Bob - the test expects none")
+	}
+	tests := []struct {
+		salutation Salutation
+		isFormal   bool
+		want       string
+	}{
+		{Salutation{Name: "Annica", Greeting: "Dearest"}, true, "Dearest, Ms Annica"},
+		{Salutation{Name: "Annica", Greeting: "Dearest"}, false, "Hey, Annica"},
+		{Salutation{Name: "Annica", Greeting: "Dearest", Locale: "es"}, true, "Buenos días, Ms Annica"},
+		{Salutation{Name: "Bob", Greeting: "Dearest"}, true, "Dearest, Bob"},
+		{Salutation{Name: "Bob", Greeting: "Dearest", Locale: "es"}, true, "Buenos días, Bob"},
+	}
+	for _, test := range tests {
+		var got string
+		IfGreet(test.salutation, func(message string) { got = message }, test.isFormal)
+		if got != test.want {
+			t.Errorf("IfGreet(%+v, %t) said %q, want %q", test.salutation, test.isFormal, got, test.want)
+		}
+	}
+}
+
+func TestTemplatesAddChecksEveryField(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		ok   bool
+	}{
+		{"plain", `{{.Greeting}}, {{.Name}}`, true},
+		{"both branches", `{{if .Formal}}{{.Prefix}} {{else}}{{.Greeting}} {{end}}{{.Name}}`, true},
+		{"root variable", `{{$.Name}}{{with .Prefix}} ({{.}}){{end}}`, true},
+		{"typo in the branch not taken", `{{if not .Formal}}{{.Nmae}}{{end}}hi`, false},
+		{"typo in else", `{{if .Formal}}hi{{else}}{{.Nmae}}{{end}}`, false},
+		{"typo through $", `{{$.Nmae}}`, false},
+		{"typo in a function argument", `{{title .Nmae}}`, false},
+		{"field of a string", `{{.Name.First}}`, false},
+		{"syntax error", `{{.Name`, false},
+	}
+	for _, test := range tests {
+		err := NewTemplates().Add(test.name, test.text)
+		if test.ok && err != nil {
+			t.Errorf("%s: Add(%q) = %v, want nil", test.name, test.text, err)
+		}
+		if !test.ok && !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("%s: Add(%q) = %v, want ErrInvalidTemplate", test.name, test.text, err)
+		}
+	}
+}
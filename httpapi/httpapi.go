@@ -0,0 +1,266 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/annicaburns/learngo/clock"
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/goMaps"
+	"github.com/annicaburns/learngo/greeting"
+)
+
+// httpapi serves the greeting logic as a JSON API so other services can call it:
+//
+//	POST   /greet               GreetRequest -> GreetResponse, rendered by greeting.MessageFor
+//	POST   /greet/formal        GreetRequest -> GreetResponse, with the name's prefix from the server's registry
+//	GET    /salutations         SalutationsResponse, from goInterfaces.VendSalutations
+//	GET    /salutations/stream  the same salutations as a stream of StreamEvents - see stream.go
+//	GET    /prefixes            PrefixesResponse - every prefix in the registry
+//	GET    /prefixes/{name}     PrefixResponse, or 404 if the name has no prefix
+//	PUT    /prefixes/{name}     PrefixRequest -> PrefixResponse - 201 for a new name, 200 for a change
+//	DELETE /prefixes/{name}     204, or 404 if the name has no prefix
+//
+// Request bodies must be JSON objects with no unknown fields, no bigger than MaxBodyBytes.
+// Every error is reported with the matching status code and an ErrorResponse body.
+
+// MaxBodyBytes is the largest request body the server reads
+const MaxBodyBytes = 1 << 20
+
+// DefaultGreeting is used by POST /greet/formal when a GreetRequest doesn't say which greeting to use
+const DefaultGreeting = greeting.DefaultGreeting
+
+// GreetRequest is the body of POST /greet and POST /greet/formal
+//
+//	{"name": "Annica", "greeting": "Howdy", "locale": "es"}
+//
+// Without a greeting, /greet uses the locale's casual message and /greet/formal uses DefaultGreeting
+type GreetRequest struct {
+	Name     string `json:"name"`               // required
+	Greeting string `json:"greeting,omitempty"` // used as given - "Howdy" gives "Howdy, Annica"
+	Locale   string `json:"locale,omitempty"`   // defaults to greeting.FallbackLocale
+}
+
+// GreetResponse is the reply to POST /greet and POST /greet/formal
+//
+//	{"message": "Howdy, Annica", "formal": false}
+type GreetResponse struct {
+	Message string `json:"message"`
+	Formal  bool   `json:"formal"`
+}
+
+// Salutation is one entry of SalutationsResponse
+type Salutation struct {
+	Name           string `json:"name"`
+	CasualGreeting string `json:"casualGreeting"`
+	FormalGreeting string `json:"formalGreeting"`
+}
+
+// SalutationsResponse is the reply to GET /salutations
+//
+//	{"salutations": [{"name": "Annica", "casualGreeting": "Howdy", "formalGreeting": "Hello"}]}
+type SalutationsResponse struct {
+	Salutations []Salutation `json:"salutations"`
+}
+
+// PrefixRequest is the body of PUT /prefixes/{name}
+//
+//	{"prefix": "Dr "}
+type PrefixRequest struct {
+	Prefix string `json:"prefix"`
+}
+
+// PrefixResponse is the reply to GET and PUT /prefixes/{name}
+//
+//	{"name": "Annica", "prefix": "Ms "}
+type PrefixResponse struct {
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+}
+
+// PrefixesResponse is the reply to GET /prefixes
+//
+//	{"fallback": "Dude ", "prefixes": [{"name": "Annica", "prefix": "Ms "}]}
+type PrefixesResponse struct {
+	Fallback string           `json:"fallback"`
+	Prefixes []PrefixResponse `json:"prefixes"`
+}
+
+// ErrorResponse is the body of every error reply
+//
+//	{"error": "name is required"}
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server holds what the API is backed by
+type Server struct {
+	Prefixes    *goMaps.PrefixRegistry
+	Salutations func() goInterfaces.Salutations
+	Clock       clock.Clock // paces streams that ask for an interval - clock.Real if nil
+	mux         *http.ServeMux
+}
+
+// NewServer creates a server backed by goMaps.Prefixes and goInterfaces.VendSalutations
+func NewServer() *Server {
+	server := &Server{Prefixes: goMaps.Prefixes, Salutations: goInterfaces.VendSalutations, mux: http.NewServeMux()}
+	server.mux.HandleFunc("POST /greet", server.greet(false))
+	server.mux.HandleFunc("POST /greet/formal", server.greet(true))
+	server.mux.HandleFunc("GET /salutations", server.listSalutations)
+	server.mux.HandleFunc("GET /salutations/stream", server.streamSalutations)
+	server.mux.HandleFunc("GET /prefixes", server.listPrefixes)
+	server.mux.HandleFunc("GET /prefixes/{name}", server.getPrefix)
+	server.mux.HandleFunc("PUT /prefixes/{name}", server.putPrefix)
+	server.mux.HandleFunc("DELETE /prefixes/{name}", server.deletePrefix)
+	return server
+}
+
+// ServeHTTP makes Server an http.Handler
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+func (server *Server) greet(isFormal bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request GreetRequest
+		if !readJSON(w, r, &request) {
+			return
+		}
+		request.Name = strings.TrimSpace(request.Name)
+		if request.Name == "" {
+			writeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		salutation := greeting.Salutation{Name: request.Name, Greeting: request.Greeting, Locale: request.Locale}
+		var prefix string
+		if isFormal {
+			prefix, _ = server.Prefixes.Lookup(request.Name)
+		}
+		message, err := greeting.MessageFor(salutation, prefix, isFormal)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, GreetResponse{Message: message, Formal: isFormal})
+	}
+}
+
+func (server *Server) listSalutations(w http.ResponseWriter, r *http.Request) {
+	response := SalutationsResponse{Salutations: []Salutation{}}
+	for _, s := range server.Salutations() {
+		response.Salutations = append(response.Salutations, Salutation{Name: s.Name, CasualGreeting: s.CasualGreeting, FormalGreeting: s.FormalGreeting})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (server *Server) listPrefixes(w http.ResponseWriter, r *http.Request) {
+	response := PrefixesResponse{Fallback: server.Prefixes.Fallback(), Prefixes: []PrefixResponse{}}
+	server.Prefixes.Range(func(name, prefix string) bool {
+		response.Prefixes = append(response.Prefixes, PrefixResponse{Name: name, Prefix: prefix})
+		return true
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (server *Server) getPrefix(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	prefix, exists := server.Prefixes.Lookup(name)
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%q has no prefix", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, PrefixResponse{Name: name, Prefix: prefix})
+}
+
+func (server *Server) putPrefix(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var request PrefixRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if strings.TrimSpace(request.Prefix) == "" {
+		writeError(w, http.StatusBadRequest, "prefix is required")
+		return
+	}
+	// Set says whether the name was there, so two PUTs racing to create it can't both be told they did
+	status := http.StatusCreated
+	if server.Prefixes.Set(name, request.Prefix) {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, PrefixResponse{Name: name, Prefix: request.Prefix})
+}
+
+func (server *Server) deletePrefix(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !server.Prefixes.Delete(name) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%q has no prefix", name))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readJSON decodes the request body into v, writing an error reply and returning false if it can't
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, "the body must be application/json")
+			return false
+		}
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err == nil && decoder.More() {
+		err = errors.New("the body must hold a single JSON object")
+	}
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &tooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("the body must be no bigger than %d bytes", MaxBodyBytes))
+	case errors.Is(err, io.EOF):
+		writeError(w, http.StatusBadRequest, "the body is empty")
+	default:
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
+
+// ListenAndServe serves handler on addr until ctx is cancelled, then gives requests in progress
+// a few seconds to finish before returning
+func ListenAndServe(ctx context.Context, addr string, handler http.Handler) error {
+	httpServer := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	failed := make(chan error, 1)
+	go func() {
+		failed <- httpServer.ListenAndServe()
+	}()
+	select {
+	case err := <-failed:
+		return fmt.Errorf("httpapi: %w", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("httpapi: shutting down: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/annicaburns/learngo/goMaps"
+)
+
+// newTestServer serves a Server with a registry of its own, so tests never touch goMaps.Prefixes
+func newTestServer(t *testing.T) (*httptest.Server, *Server) {
+	t.Helper()
+	server := NewServer()
+	server.Prefixes = goMaps.NewPrefixRegistry(goMaps.DefaultPrefix, map[string]string{"Annica": "Ms "})
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return httpServer, server
+}
+
+// call sends body (if it isn't empty) as JSON, and decodes the reply into reply (if it isn't nil)
+func call(t *testing.T, httpServer *httptest.Server, method, path, body string, reply interface{}) *http.Response {
+	t.Helper()
+	request, err := http.NewRequest(method, httpServer.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := httpServer.Client().Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if reply != nil {
+		if err := json.NewDecoder(response.Body).Decode(reply); err != nil {
+			t.Fatalf("%s %s: decoding the reply: %v", method, path, err)
+		}
+	}
+	return response
+}
+
+func TestGreet(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	tests := []struct {
+		path string
+		body string
+		want GreetResponse
+	}{
+		{"/greet", `{"name": "Annica", "greeting": "Howdy"}`, GreetResponse{Message: "Howdy, Annica"}},
+		{"/greet", `{"name": "Annica"}`, GreetResponse{Message: "Hey, Annica"}},
+		{"/greet", `{"name": "Bob", "locale": "es"}`, GreetResponse{Message: "Hola, Bob"}},
+		{"/greet/formal", `{"name": "Annica", "greeting": "Good evening"}`, GreetResponse{Message: "Good evening, Ms Annica", Formal: true}},
+		{"/greet/formal", `{"name": "Annica"}`, GreetResponse{Message: "Hello, Ms Annica", Formal: true}},
+		// a name with no prefix is greeted neutrally rather than with the registry's fallback
+		{"/greet/formal", `{"name": "Bob"}`, GreetResponse{Message: "Hello, Bob", Formal: true}},
+	}
+	for _, test := range tests {
+		var reply GreetResponse
+		response := call(t, httpServer, http.MethodPost, test.path, test.body, &reply)
+		if response.StatusCode != http.StatusOK || reply != test.want {
+			t.Errorf("POST %s %s = %d %+v, want %+v", test.path, test.body, response.StatusCode, reply, test.want)
+		}
+	}
+}
+
+func TestFormalGreetingUsesPrefixFromPut(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	if response := call(t, httpServer, http.MethodPut, "/prefixes/Bob", `{"prefix": "Dr"}`, nil); response.StatusCode != http.StatusCreated {
+		t.Fatalf("PUT /prefixes/Bob = %d, want 201", response.StatusCode)
+	}
+	var reply GreetResponse
+	call(t, httpServer, http.MethodPost, "/greet/formal", `{"name": "Bob", "locale": "es"}`, &reply)
+	if reply.Message != "Buenos días, Dr Bob" {
+		t.Errorf("POST /greet/formal = %q, want %q", reply.Message, "Buenos días, Dr Bob")
+	}
+}
+
+func TestListSalutations(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	var reply SalutationsResponse
+	response := call(t, httpServer, http.MethodGet, "/salutations", "", &reply)
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("GET /salutations = %d %s", response.StatusCode, response.Header.Get("Content-Type"))
+	}
+	if len(reply.Salutations) != 3 || reply.Salutations[0] != (Salutation{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello"}) {
+		t.Errorf("GET /salutations = %+v", reply.Salutations)
+	}
+}
+
+func TestPrefixRoundTrip(t *testing.T) {
+	httpServer, server := newTestServer(t)
+	steps := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/prefixes/Bob", "", http.StatusNotFound},
+		{http.MethodPut, "/prefixes/Bob", `{"prefix": "Dr "}`, http.StatusCreated},
+		{http.MethodGet, "/prefixes/Bob", "", http.StatusOK},
+		{http.MethodPut, "/prefixes/Bob", `{"prefix": "Prof "}`, http.StatusOK},
+		{http.MethodDelete, "/prefixes/Bob", "", http.StatusNoContent},
+		{http.MethodDelete, "/prefixes/Bob", "", http.StatusNotFound},
+		{http.MethodGet, "/prefixes/Bob", "", http.StatusNotFound},
+	}
+	for i, step := range steps {
+		response := call(t, httpServer, step.method, step.path, step.body, nil)
+		if response.StatusCode != step.status {
+			t.Fatalf("step %d: %s %s = %d, want %d", i, step.method, step.path, response.StatusCode, step.status)
+		}
+		if i == 3 {
+			var reply PrefixResponse
+			call(t, httpServer, http.MethodGet, "/prefixes/Bob", "", &reply)
+			if reply != (PrefixResponse{Name: "Bob", Prefix: "Prof "}) {
+				t.Fatalf("after the second PUT, GET /prefixes/Bob = %+v", reply)
+			}
+		}
+	}
+	if server.Prefixes.Exists("Bob") {
+		t.Error("Bob is still in the registry after DELETE")
+	}
+}
+
+func TestListPrefixes(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	call(t, httpServer, http.MethodPut, "/prefixes/Bob", `{"prefix": "Dr "}`, nil)
+	var reply PrefixesResponse
+	call(t, httpServer, http.MethodGet, "/prefixes", "", &reply)
+	want := []PrefixResponse{{Name: "Annica", Prefix: "Ms "}, {Name: "Bob", Prefix: "Dr "}}
+	if reply.Fallback != goMaps.DefaultPrefix || len(reply.Prefixes) != 2 || reply.Prefixes[0] != want[0] || reply.Prefixes[1] != want[1] {
+		t.Errorf("GET /prefixes = %+v, want %+v", reply, want)
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"no name", http.MethodPost, "/greet", "application/json", `{"greeting": "Howdy"}`, http.StatusBadRequest},
+		{"blank name", http.MethodPost, "/greet/formal", "application/json", `{"name": "  "}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/greet", "application/json", `{"name": "Annica", "nickname": "Nic"}`, http.StatusBadRequest},
+		{"empty body", http.MethodPost, "/greet", "application/json", ``, http.StatusBadRequest},
+		{"invalid JSON", http.MethodPost, "/greet", "application/json", `{"name": `, http.StatusBadRequest},
+		{"two objects", http.MethodPost, "/greet", "application/json", `{"name": "Annica"} {}`, http.StatusBadRequest},
+		{"blank prefix", http.MethodPut, "/prefixes/Bob", "application/json", `{"prefix": " "}`, http.StatusBadRequest},
+		{"text body", http.MethodPost, "/greet", "text/plain", `{"name": "Annica"}`, http.StatusUnsupportedMediaType},
+		{"form body", http.MethodPut, "/prefixes/Bob", "application/x-www-form-urlencoded", `prefix=Dr`, http.StatusUnsupportedMediaType},
+		{"too big", http.MethodPost, "/greet", "application/json", `{"name": "` + strings.Repeat("a", MaxBodyBytes) + `"}`, http.StatusRequestEntityTooLarge},
+		{"unknown route", http.MethodGet, "/farewell", "", ``, http.StatusNotFound},
+		{"wrong method", http.MethodGet, "/greet", "", ``, http.StatusMethodNotAllowed},
+	}
+	for _, test := range tests {
+		request, err := http.NewRequest(test.method, httpServer.URL+test.path, strings.NewReader(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.contentType != "" {
+			request.Header.Set("Content-Type", test.contentType)
+		}
+		response, err := httpServer.Client().Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(response.Body)
+		response.Body.Close()
+		if response.StatusCode != test.status {
+			t.Errorf("%s: %s %s = %d, want %d: %s", test.name, test.method, test.path, response.StatusCode, test.status, body)
+			continue
+		}
+		// the mux answers unknown routes itself, everything else carries an ErrorResponse
+		if test.status == http.StatusNotFound || test.status == http.StatusMethodNotAllowed {
+			continue
+		}
+		var reply ErrorResponse
+		if err := json.Unmarshal(body, &reply); err != nil || reply.Error == "" {
+			t.Errorf("%s: the reply %q isn't an ErrorResponse", test.name, body)
+		}
+	}
+}
+
+func TestConcurrentPutsCreateOnce(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	for round := 0; round < 20; round++ {
+		path := fmt.Sprintf("/prefixes/Person%d", round)
+		statuses := make(chan int, 8)
+		var wg sync.WaitGroup
+		for i := 0; i < cap(statuses); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// not call, which can't fail the test from another goroutine
+				request, _ := http.NewRequest(http.MethodPut, httpServer.URL+path, strings.NewReader(fmt.Sprintf(`{"prefix": "Dr %d"}`, i)))
+				response, err := httpServer.Client().Do(request)
+				if err != nil {
+					statuses <- 0
+					return
+				}
+				response.Body.Close()
+				statuses <- response.StatusCode
+			}()
+		}
+		wg.Wait()
+		close(statuses)
+		created := 0
+		for status := range statuses {
+			if status == http.StatusCreated {
+				created++
+			} else if status != http.StatusOK {
+				t.Fatalf("PUT %s = %d", path, status)
+			}
+		}
+		if created != 1 {
+			t.Fatalf("%d concurrent PUTs of %s answered 201, want exactly one", created, path)
+		}
+	}
+}
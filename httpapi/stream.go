@@ -0,0 +1,158 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/annicaburns/learngo/goConcurrency"
+	"github.com/annicaburns/learngo/goInterfaces"
+)
+
+// GET /salutations/stream sends the salutations to the client one at a time as ChannelGreeterContext produces them,
+// like goConcurrency.ChannelWithRange does over HTTP. It speaks two formats:
+//
+//	text/event-stream     server-sent events, for a browser's EventSource - chosen by "Accept: text/event-stream"
+//	application/x-ndjson  one StreamEvent of JSON per line - the default
+//
+// ?format=sse or ?format=ndjson overrides the Accept header, and ?interval=500ms spaces the salutations out.
+// Every salutation carries an id - its position in the roster, counting from 1. A client that is cut off can resume
+// after the last id it saw with the Last-Event-ID header (which EventSource sends by itself when it reconnects)
+// or with ?after=<id>. When a client disconnects, its request context is cancelled and that stops the producer.
+
+// StreamEvent is one salutation in the stream
+//
+//	{"id": 1, "name": "Annica", "casualGreeting": "Howdy", "formalGreeting": "Hello"}
+type StreamEvent struct {
+	ID int `json:"id"`
+	Salutation
+}
+
+// streamFormat writes the events of one stream format
+type streamFormat struct {
+	contentType string
+	event       func(w http.ResponseWriter, event StreamEvent) error
+	end         func(w http.ResponseWriter) error
+}
+
+var (
+	sseFormat = streamFormat{
+		contentType: "text/event-stream",
+		event: func(w http.ResponseWriter, event StreamEvent) error {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "id: %d\nevent: salutation\ndata: %s\n\n", event.ID, data)
+			return err
+		},
+		// an EventSource reconnects whenever the connection closes, so tell it the stream really is over
+		end: func(w http.ResponseWriter) error {
+			_, err := fmt.Fprint(w, "event: end\ndata: {}\n\n")
+			return err
+		},
+	}
+	ndjsonFormat = streamFormat{
+		contentType: "application/x-ndjson",
+		event: func(w http.ResponseWriter, event StreamEvent) error {
+			return json.NewEncoder(w).Encode(event)
+		},
+		end: func(w http.ResponseWriter) error { return nil },
+	}
+)
+
+// chooseFormat picks the stream format from ?format= or the Accept header
+func chooseFormat(r *http.Request) (streamFormat, bool) {
+	switch r.URL.Query().Get("format") {
+	case "sse":
+		return sseFormat, true
+	case "ndjson":
+		return ndjsonFormat, true
+	case "":
+	default:
+		return streamFormat{}, false
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted)); err == nil && mediaType == sseFormat.contentType {
+			return sseFormat, true
+		}
+	}
+	return ndjsonFormat, true
+}
+
+// resumeAfter returns the id the client has already seen up to, from Last-Event-ID or ?after=
+func resumeAfter(r *http.Request) (int, error) {
+	after := r.Header.Get("Last-Event-ID")
+	if query := r.URL.Query().Get("after"); query != "" {
+		after = query
+	}
+	if after == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(after)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("%q is not a valid event id", after)
+	}
+	return id, nil
+}
+
+func (server *Server) streamSalutations(w http.ResponseWriter, r *http.Request) {
+	format, ok := chooseFormat(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "format must be sse or ndjson")
+		return
+	}
+	after, err := resumeAfter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var interval time.Duration
+	if query := r.URL.Query().Get("interval"); query != "" {
+		if interval, err = time.ParseDuration(query); err != nil || interval < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("%q is not a valid interval", query))
+			return
+		}
+	}
+
+	salutations := server.Salutations()
+	if after > len(salutations) {
+		after = len(salutations)
+	}
+	// the request context is cancelled when the client goes away, which stops the producer
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	var channel <-chan goInterfaces.Salutation = salutations[after:].Channel(ctx)
+	if interval > 0 {
+		channel = goConcurrency.Throttle(ctx, server.Clock, channel, interval)
+	}
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	controller.Flush()
+
+	id := after
+	for salutation := range channel {
+		id++
+		event := StreamEvent{ID: id, Salutation: Salutation{Name: salutation.Name, CasualGreeting: salutation.CasualGreeting, FormalGreeting: salutation.FormalGreeting}}
+		if err := format.event(w, event); err != nil {
+			// the client has gone - returning cancels ctx and the deferred cancel stops the producer
+			return
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+	if ctx.Err() == nil {
+		format.end(w)
+		controller.Flush()
+	}
+}
@@ -0,0 +1,214 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/clock"
+	"github.com/annicaburns/learngo/goInterfaces"
+)
+
+// stream opens GET url with the given headers, failing the test unless the reply has status
+func stream(t *testing.T, ctx context.Context, url string, headers map[string]string, status int) *http.Response {
+	t.Helper()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != status {
+		body, _ := io.ReadAll(response.Body)
+		response.Body.Close()
+		t.Fatalf("GET %s = %d, want %d: %s", url, response.StatusCode, status, body)
+	}
+	return response
+}
+
+// sseEvent is one event read back from a text/event-stream
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// readSSE reads events until the stream ends
+func readSSE(body io.Reader) (events []sseEvent) {
+	var event sseEvent
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		field, value, _ := strings.Cut(scanner.Text(), ": ")
+		switch field {
+		case "id":
+			event.id = value
+		case "event":
+			event.event = value
+		case "data":
+			event.data = value
+		case "":
+			events = append(events, event)
+			event = sseEvent{}
+		}
+	}
+	return events
+}
+
+// readNDJSON reads StreamEvents until the stream ends
+func readNDJSON(t *testing.T, body io.Reader) (events []StreamEvent) {
+	t.Helper()
+	decoder := json.NewDecoder(body)
+	for {
+		var event StreamEvent
+		if err := decoder.Decode(&event); err == io.EOF {
+			return events
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+}
+
+func TestStreamSSEResumesAfterLastEventID(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	tests := []struct {
+		lastEventID string
+		wantIDs     []string
+		wantNames   []string
+	}{
+		{"", []string{"1", "2", "3"}, []string{"Annica", "Mitchel", "Marisol"}},
+		{"1", []string{"2", "3"}, []string{"Mitchel", "Marisol"}},
+		{"3", nil, nil},
+		{"99", nil, nil},
+	}
+	for _, test := range tests {
+		headers := map[string]string{"Accept": "text/event-stream"}
+		if test.lastEventID != "" {
+			headers["Last-Event-ID"] = test.lastEventID
+		}
+		response := stream(t, context.Background(), httpServer.URL+"/salutations/stream", headers, http.StatusOK)
+		events := readSSE(response.Body)
+		response.Body.Close()
+		if response.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("Content-Type = %q", response.Header.Get("Content-Type"))
+		}
+		if len(events) == 0 || events[len(events)-1].event != "end" {
+			t.Fatalf("Last-Event-ID %q: the stream didn't finish with an end event: %+v", test.lastEventID, events)
+		}
+		events = events[:len(events)-1]
+		if len(events) != len(test.wantIDs) {
+			t.Fatalf("Last-Event-ID %q: got %d events, want %d", test.lastEventID, len(events), len(test.wantIDs))
+		}
+		for i, event := range events {
+			var data StreamEvent
+			if err := json.Unmarshal([]byte(event.data), &data); err != nil {
+				t.Fatal(err)
+			}
+			if event.id != test.wantIDs[i] || event.event != "salutation" || data.Name != test.wantNames[i] {
+				t.Errorf("Last-Event-ID %q: event %d = %+v, want id %s for %s", test.lastEventID, i, event, test.wantIDs[i], test.wantNames[i])
+			}
+		}
+	}
+}
+
+func TestStreamNDJSONResumesAfterQuery(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	// ?after= wins over Last-Event-ID
+	response := stream(t, context.Background(), httpServer.URL+"/salutations/stream?after=2", map[string]string{"Last-Event-ID": "0"}, http.StatusOK)
+	defer response.Body.Close()
+	if response.Header.Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("Content-Type = %q", response.Header.Get("Content-Type"))
+	}
+	events := readNDJSON(t, response.Body)
+	want := StreamEvent{ID: 3, Salutation: Salutation{Name: "Marisol", CasualGreeting: "Salud", FormalGreeting: "Hello"}}
+	if len(events) != 1 || events[0] != want {
+		t.Errorf("events = %+v, want [%+v]", events, want)
+	}
+}
+
+func TestStreamRejectsBadParameters(t *testing.T) {
+	httpServer, _ := newTestServer(t)
+	tests := []struct {
+		query   string
+		headers map[string]string
+	}{
+		{"?after=-1", nil},
+		{"?after=two", nil},
+		{"", map[string]string{"Last-Event-ID": "-1"}},
+		{"?format=xml", nil},
+		{"?interval=-1s", nil},
+		{"?interval=soon", nil},
+	}
+	for _, test := range tests {
+		response := stream(t, context.Background(), httpServer.URL+"/salutations/stream"+test.query, test.headers, http.StatusBadRequest)
+		var reply ErrorResponse
+		if err := json.NewDecoder(response.Body).Decode(&reply); err != nil || reply.Error == "" {
+			t.Errorf("%s %v: the reply isn't an ErrorResponse", test.query, test.headers)
+		}
+		response.Body.Close()
+	}
+}
+
+func TestStreamIntervalOnAFakeClock(t *testing.T) {
+	httpServer, server := newTestServer(t)
+	fake := clock.NewFake(time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC))
+	server.Clock = fake
+	response := stream(t, context.Background(), httpServer.URL+"/salutations/stream?interval=1s", nil, http.StatusOK)
+	defer response.Body.Close()
+	decoder := json.NewDecoder(response.Body)
+	for i, name := range []string{"Annica", "Mitchel", "Marisol"} {
+		if i > 0 {
+			fake.BlockUntil(1)
+			fake.Advance(time.Second)
+		}
+		var event StreamEvent
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatal(err)
+		}
+		if event.ID != i+1 || event.Name != name {
+			t.Fatalf("event %d = %+v, want %s", i, event, name)
+		}
+	}
+}
+
+func TestStreamStopsWhenTheClientGoes(t *testing.T) {
+	httpServer, server := newTestServer(t)
+	// a long roster and a clock that never moves, so the stream can only end because the client left
+	server.Clock = clock.NewFake(time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC))
+	server.Salutations = func() goInterfaces.Salutations {
+		salutations := make(goInterfaces.Salutations, 1000)
+		for i := range salutations {
+			salutations[i] = goInterfaces.Salutation{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello"}
+		}
+		return salutations
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	response := stream(t, ctx, httpServer.URL+"/salutations/stream?interval=1s", nil, http.StatusOK)
+	var event StreamEvent
+	if err := json.NewDecoder(response.Body).Decode(&event); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	response.Body.Close()
+	// Close waits for every handler to return
+	closed := make(chan struct{})
+	go func() {
+		httpServer.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream handler kept running after the client went away")
+	}
+}
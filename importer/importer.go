@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/annicaburns/learngo/codec"
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/store"
+)
+
+// Import loads a roster file - usually a CSV export from a spreadsheet - into a store.SalutationStore.
+// It sits between codec, which reads the file, and store, which keeps the roster, so neither depends on the other.
+// Every record is checked before anything is written, and each problem is reported with the line it is on:
+//   - a record the codec can't read, such as a CSV row with the wrong number of columns
+//   - an empty name (a name is trimmed of spaces first - spreadsheets are full of them)
+//   - a field that isn't valid UTF-8, which usually means the file was saved in a legacy encoding
+//   - a name used by an earlier record in the file
+//   - a name that is already in the store, unless Options.Update is set
+// When there is a problem nothing is written, so the file can be fixed and imported again from the start.
+// If the store fails part way through writing, Import undoes what it wrote:
+//   - a store.Batcher (MemoryStore, JSONFileStore) writes every salutation at once and changes nothing if it fails
+//   - any other store (KVStore) is written one salutation at a time, and when a write fails the salutations
+//     already written are deleted or put back as they were, last first
+// Undoing can fail too - the disk that refused a write may refuse the next one - and then the error says so.
+// A program killed in the middle of the writes leaves them half done either way.
+
+// ErrBadEncoding is reported for a field that isn't valid UTF-8
+var ErrBadEncoding = errors.New("importer: not valid UTF-8")
+
+// Options change what Import does
+type Options struct {
+	DryRun      bool // check every record and report what would happen, but write nothing
+	Update      bool // replace salutations already in the store rather than reporting them as problems
+	SkipInvalid bool // write the valid records even when others have problems
+}
+
+// Report is what Import found and did
+type Report struct {
+	Records  int                  // records read from the file
+	Created  int                  // new salutations
+	Updated  int                  // salutations replaced because of Options.Update
+	Problems []*codec.DecodeError // every record that wasn't imported, in the order they appear in the file
+	Written  bool                 // whether Created and Updated were written - false for a dry run, when problems stopped the import, or when writing failed
+}
+
+// record is a valid record waiting to be written, with what the store held for its name
+type record struct {
+	salutation core.Salutation
+	original   core.Salutation
+	exists     bool
+}
+
+// Import reads every salutation from r with fileCodec and adds it to into.
+// The error is only for failures that stop the import part way, such as a CSV header naming an unknown column
+// or the store failing to write - a problem with a single record ends up in Report.Problems
+func Import(fileCodec codec.Codec, r io.Reader, into store.SalutationStore, options Options) (report Report, err error) {
+	decoder := fileCodec.NewDecoder(r)
+	position, _ := decoder.(codec.Positioner)
+	lines := make(map[string]int) // the line of every name seen so far
+	var valid []record
+	for {
+		salutation, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		var decodeError *codec.DecodeError
+		if errors.As(err, &decodeError) && decodeError.Record > 0 {
+			report.Records++
+			report.Problems = append(report.Problems, decodeError)
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+		report.Records++
+		number, line := report.Records, 0
+		if position != nil {
+			number, line = position.Position()
+		}
+		problem := func(err error) {
+			report.Problems = append(report.Problems, &codec.DecodeError{Format: fileCodec.Name(), Record: number, Line: line, Err: err})
+		}
+
+		salutation.Name = strings.TrimSpace(salutation.Name)
+		if field, bad := invalidUTF8(salutation); bad {
+			problem(fmt.Errorf("%w in %s", ErrBadEncoding, field))
+			continue
+		}
+		if salutation.Name == "" {
+			problem(store.ErrNoName)
+			continue
+		}
+		if earlier, seen := lines[salutation.Name]; seen {
+			problem(fmt.Errorf("%q is already on line %d", salutation.Name, earlier))
+			continue
+		}
+		lines[salutation.Name] = line
+		original, err := into.Get(salutation.Name)
+		exists := err == nil
+		switch {
+		case err != nil && !errors.Is(err, store.ErrNotFound):
+			return report, err
+		case exists && !options.Update:
+			problem(fmt.Errorf("%w: %q", store.ErrExists, salutation.Name))
+			continue
+		}
+		valid = append(valid, record{salutation, original, exists})
+	}
+
+	for _, record := range valid {
+		if record.exists {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+	}
+	if options.DryRun || (len(report.Problems) > 0 && !options.SkipInvalid) {
+		return report, nil
+	}
+	if err := write(into, valid); err != nil {
+		return report, err
+	}
+	report.Written = true
+	return report, nil
+}
+
+// write puts every record in the store, undoing the ones it wrote if one of them fails
+func write(into store.SalutationStore, records []record) error {
+	if batcher, ok := into.(store.Batcher); ok {
+		// a JSONFileStore rewrites its file for every Create, which takes minutes for a big roster
+		salutations := make(core.Salutations, len(records))
+		for i, record := range records {
+			salutations[i] = record.salutation
+		}
+		return batcher.PutAll(salutations)
+	}
+	for i, record := range records {
+		put := into.Create
+		if record.exists {
+			put = into.Update
+		}
+		if err := put(record.salutation); err != nil {
+			if undoErr := undo(into, records[:i]); undoErr != nil {
+				return fmt.Errorf("importer: writing %q: %w - and undoing the %d salutations before it: %w", record.salutation.Name, err, i, undoErr)
+			}
+			return fmt.Errorf("importer: writing %q: %w - nothing was imported", record.salutation.Name, err)
+		}
+	}
+	return nil
+}
+
+// undo deletes the records that were created and puts back the ones that were updated, last first,
+// carrying on past failures so as much as possible is undone
+func undo(into store.SalutationStore, written []record) error {
+	var errs []error
+	for i := len(written) - 1; i >= 0; i-- {
+		record := written[i]
+		if record.exists {
+			errs = append(errs, into.Update(record.original))
+		} else {
+			errs = append(errs, into.Delete(record.salutation.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// invalidUTF8 returns the name of the first field that isn't valid UTF-8
+func invalidUTF8(salutation core.Salutation) (string, bool) {
+	for _, field := range []struct{ name, value string }{
+		{"name", salutation.Name},
+		{"casualGreeting", salutation.CasualGreeting},
+		{"formalGreeting", salutation.FormalGreeting},
+		{"prefix", salutation.Prefix},
+		{"locale", salutation.Locale},
+	} {
+		if !utf8.ValidString(field.value) {
+			return field.name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/annicaburns/learngo/codec"
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/store"
+)
+
+// failingStore is a store that isn't a store.Batcher, and fails each Create or Update that fails picks
+type failingStore struct {
+	store.SalutationStore
+	fails func(core.Salutation) bool
+}
+
+var errDiskFull = errors.New("disk full")
+
+func (failing *failingStore) write(put func(core.Salutation) error, salutation core.Salutation) error {
+	if failing.fails(salutation) {
+		return errDiskFull
+	}
+	return put(salutation)
+}
+
+func (failing *failingStore) Create(salutation core.Salutation) error {
+	return failing.write(failing.SalutationStore.Create, salutation)
+}
+
+func (failing *failingStore) Update(salutation core.Salutation) error {
+	return failing.write(failing.SalutationStore.Update, salutation)
+}
+
+// existing returns a store holding Annica, with nothing that makes it a store.Batcher
+func existing(t *testing.T) store.SalutationStore {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	if err := memory.Create(core.Salutation{Name: "Annica", CasualGreeting: "Howdy"}); err != nil {
+		t.Fatal(err)
+	}
+	return struct{ store.SalutationStore }{memory}
+}
+
+func list(t *testing.T, into store.SalutationStore) core.Salutations {
+	t.Helper()
+	salutations, err := into.List(store.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return salutations
+}
+
+const roster = "name,casualGreeting\n Bob ,Hi\nAnnica,Hey\nMitchel,Yo\n"
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		options  Options
+		report   Report
+		problems int
+		names    []string
+	}{
+		{"existing name", roster, Options{}, Report{Records: 3, Created: 2}, 1, []string{"Annica"}},
+		{"update", roster, Options{Update: true}, Report{Records: 3, Created: 2, Updated: 1, Written: true}, 0, []string{"Annica", "Bob", "Mitchel"}},
+		{"skip invalid", roster, Options{SkipInvalid: true}, Report{Records: 3, Created: 2, Written: true}, 1, []string{"Annica", "Bob", "Mitchel"}},
+		{"dry run", roster, Options{DryRun: true, Update: true}, Report{Records: 3, Created: 2, Updated: 1}, 0, []string{"Annica"}},
+		{"repeated name", "name\nBob\nMitchel\nBob\n", Options{}, Report{Records: 3, Created: 2}, 1, []string{"Annica"}},
+		{"no name", "name,prefix\n  ,Dr\nBob,\n", Options{}, Report{Records: 2, Created: 1}, 1, []string{"Annica"}},
+		{"bad encoding", "name,casualGreeting\nBob,\xffHi\n", Options{SkipInvalid: true}, Report{Records: 1, Written: true}, 1, []string{"Annica"}},
+		{"wrong columns", "name,prefix\nBob,Dr,extra\nMitchel,Mr\n", Options{SkipInvalid: true}, Report{Records: 2, Created: 1, Written: true}, 1, []string{"Annica", "Mitchel"}},
+	}
+	for _, test := range tests {
+		into := existing(t)
+		report, err := Import(codec.CSV, strings.NewReader(test.input), into, test.options)
+		if err != nil {
+			t.Errorf("%s: Import = %v", test.name, err)
+			continue
+		}
+		problems := report.Problems
+		report.Problems = nil
+		if report.Records != test.report.Records || report.Created != test.report.Created || report.Updated != test.report.Updated || report.Written != test.report.Written {
+			t.Errorf("%s: report %+v, want %+v", test.name, report, test.report)
+		}
+		if len(problems) != test.problems {
+			t.Errorf("%s: %d problems %v, want %d", test.name, len(problems), problems, test.problems)
+		}
+		var names []string
+		for _, salutation := range list(t, into) {
+			names = append(names, salutation.Name)
+		}
+		if !slices.Equal(names, test.names) {
+			t.Errorf("%s: the store holds %v, want %v", test.name, names, test.names)
+		}
+	}
+}
+
+func TestImportReportsProblemLines(t *testing.T) {
+	report, err := Import(codec.CSV, strings.NewReader(roster), existing(t), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Problems) != 1 || report.Problems[0].Line != 3 || !errors.Is(report.Problems[0], store.ErrExists) {
+		t.Errorf("problems %v, want Annica already existing on line 3", report.Problems)
+	}
+}
+
+func TestImportUndoesPartialWrites(t *testing.T) {
+	into := existing(t)
+	failing := &failingStore{SalutationStore: into, fails: func(salutation core.Salutation) bool { return salutation.Name == "Mitchel" }}
+	// Bob is created and Annica updated before Mitchel fails
+	report, err := Import(codec.CSV, strings.NewReader(roster), failing, Options{Update: true})
+	if !errors.Is(err, errDiskFull) {
+		t.Fatalf("Import = %v, want the write error", err)
+	}
+	if report.Written {
+		t.Error("the report says the salutations were written")
+	}
+	want := core.Salutations{{Name: "Annica", CasualGreeting: "Howdy"}}
+	if got := list(t, into); !slices.Equal(got, want) {
+		t.Errorf("after a failed import the store holds %v, want %v", got, want)
+	}
+}
+
+func TestImportReportsAFailedUndo(t *testing.T) {
+	into := existing(t)
+	// one write succeeds, then everything fails - including putting Annica back
+	writes := 0
+	failing := &failingStore{SalutationStore: into, fails: func(core.Salutation) bool { writes++; return writes > 1 }}
+	_, err := Import(codec.CSV, strings.NewReader("name,casualGreeting\nAnnica,Hey\nBob,Hi\n"), failing, Options{Update: true})
+	if !errors.Is(err, errDiskFull) || !strings.Contains(err.Error(), "undoing") {
+		t.Errorf("Import = %v, want it to say undoing failed", err)
+	}
+}
+
+func TestImportIntoABatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roster.json")
+	into, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer into.Close()
+	if _, ok := into.(store.Batcher); !ok {
+		t.Fatal("a JSONFileStore is no longer a store.Batcher")
+	}
+	report, err := Import(codec.CSV, strings.NewReader(roster), into, Options{})
+	if err != nil || !report.Written || report.Created != 3 {
+		t.Fatalf("Import = %+v, %v", report, err)
+	}
+	if got := list(t, into); len(got) != 3 {
+		t.Errorf("the store holds %v, want 3 salutations", got)
+	}
+}
@@ -1,22 +1,31 @@
 package main
 
 import (
-	"github.com/annicaburns/learngo/goSwitch"
-	"github.com/annicaburns/learngo/greeting"
+	"os"
+
+	// Each demo package registers its demos with the registry when it is imported
+	_ "github.com/annicaburns/learngo/codec"
+	_ "github.com/annicaburns/learngo/goCollections"
+	_ "github.com/annicaburns/learngo/goConcurrency"
+	_ "github.com/annicaburns/learngo/goInterfaces"
+	_ "github.com/annicaburns/learngo/goLoops"
+	_ "github.com/annicaburns/learngo/goMaps"
+	_ "github.com/annicaburns/learngo/goSwitch"
+	_ "github.com/annicaburns/learngo/greeting"
+	_ "github.com/annicaburns/learngo/paging"
+	_ "github.com/annicaburns/learngo/rotation"
 )
 
+// Run any demo by name instead of editing this file and recompiling:
+//
+//	learngo list
+//	learngo run goLoops.InfiniteLoop -name Mitchel -times 2
+//	learngo serve -addr localhost:8080 -rpc localhost:8081
+//	learngo import -roster roster.json -dry-run people.csv
+//	learngo salutations query 'name ~ "^Ma" and formal = Hello sort by name desc'
+//	learngo help run
 func main() {
-	// greeting.PointerExample()
-	var sal = greeting.Salutation{Name: "Annica", Greeting: "Dearest"}
-	// fmt.Println(goSwitch.SwitchNothing())
-	goSwitch.SwitchType(sal)
-	// goLoops.CollectionLoop()
-	// fmt.Println(goMaps.MapDelete("Jo"))
-	// goCollections.PrintSmallerSlice()
-	// goInterfaces.PrintRenamable()
-	// goInterfaces.PrintWriterType()
-	// greeting.PrintVariadicGreet()
-	// goConcurrency.ConcurrencySelect()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
 }
 
 /*
@@ -0,0 +1,75 @@
+package paging
+
+import (
+	"context"
+
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goInterfaces"
+)
+
+// ChannelSource reads from the goInterfaces.ChannelGreeter producer, in the order it sends.
+// A channel can't be rewound, so carrying on from a cursor starts a new producer and throws away
+// the salutations the cursor has already read. Close stops the producer, so an iterator left part way never leaks it
+type ChannelSource struct {
+	Salutations goInterfaces.Salutations
+}
+
+// Iterate starts a producer and skips the salutations before cursor
+func (source ChannelSource) Iterate(ctx context.Context, cursor Cursor) (Iterator, error) {
+	state, err := decodeCursor(cursor, "channel")
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	channel := make(chan goInterfaces.Salutation)
+	go source.Salutations.ChannelGreeterContext(ctx, channel)
+	it := &channelIterator{ctx: ctx, cancel: cancel, channel: channel, state: state}
+	for skip := state.Count; skip > 0; skip-- {
+		if _, ok := <-channel; !ok {
+			break
+		}
+	}
+	return it, nil
+}
+
+type channelIterator struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	channel <-chan goInterfaces.Salutation
+	current core.Salutation
+	state   cursorState
+	err     error
+	closed  bool
+}
+
+func (it *channelIterator) Next() bool {
+	if it.closed {
+		return false
+	}
+	salutation, ok := <-it.channel
+	if !ok {
+		// the producer closes the channel when it has sent everything, or when ctx is cancelled
+		it.err = it.ctx.Err()
+		it.closed = true
+		return false
+	}
+	it.current = core.FromInterfaces(salutation)
+	it.state.Count++
+	return true
+}
+
+func (it *channelIterator) Salutation() core.Salutation { return it.current }
+func (it *channelIterator) Cursor() Cursor              { return it.state.encode() }
+func (it *channelIterator) Err() error                  { return it.err }
+
+func (it *channelIterator) Close() error {
+	if !it.closed {
+		it.closed = true
+		it.cancel()
+		// wait for the producer to see the cancellation and close the channel
+		for range it.channel {
+		}
+	}
+	it.cancel()
+	return nil
+}
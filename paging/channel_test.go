@@ -0,0 +1,87 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goInterfaces"
+)
+
+// checkNoLeaks fails the test if it ends with more goroutines running than when it started
+func checkNoLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if after := runtime.NumGoroutine(); after > before {
+			stacks := make([]byte, 1<<16)
+			stacks = stacks[:runtime.Stack(stacks, true)]
+			t.Errorf("%d goroutines leaked:\n%s", after-before, stacks)
+		}
+	})
+}
+
+func TestChannelSourceResumesBySkipping(t *testing.T) {
+	checkNoLeaks(t)
+	source := ChannelSource{Salutations: goInterfaces.VendSalutations()}
+	want := core.FromSalutations(source.Salutations)
+	for size := 1; size <= len(want)+1; size++ {
+		if all, _ := readAll(t, source, size); !slices.Equal(all, want) {
+			t.Errorf("in pages of %d read %q, want %q", size, all, want)
+		}
+	}
+	page, err := ReadPage(context.Background(), source, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err = ReadPage(context.Background(), source, page.Next, 1)
+	if err != nil || !slices.Equal(page.Salutations, want[1:2]) {
+		t.Errorf("the second page = %q, %v, want %q", page.Salutations, err, want[1:2])
+	}
+	// a cursor past the end carries on from the end
+	beyond := (cursorState{Kind: "channel", Count: 10}).encode()
+	if page, err := ReadPage(context.Background(), source, beyond, 1); err != nil || len(page.Salutations) != 0 || page.Next != "" {
+		t.Errorf("ReadPage past the end = %+v, %v", page, err)
+	}
+}
+
+func TestChannelIteratorCloseStopsTheProducer(t *testing.T) {
+	checkNoLeaks(t)
+	source := ChannelSource{Salutations: goInterfaces.VendSalutations()}
+	it, err := source.Iterate(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !it.Next() {
+		t.Fatalf("Next = false, %v", it.Err())
+	}
+	// the producer is blocked sending the second salutation until Close
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := it.Close(); err != nil {
+		t.Errorf("a second Close = %v", err)
+	}
+	if it.Next() {
+		t.Error("Next after Close = true")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it, _ = source.Iterate(ctx, "")
+	defer it.Close()
+	it.Next()
+	cancel()
+	for it.Next() {
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err after cancelling = %v, want context.Canceled", it.Err())
+	}
+}
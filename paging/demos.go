@@ -0,0 +1,71 @@
+package paging
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/registry"
+	"github.com/annicaburns/learngo/store"
+)
+
+// Parameters only the paging demos use
+var (
+	sourceParam = registry.Param{Name: "source", Kind: registry.String, Default: "slice", Description: "slice (the roster in memory), file (a codec file read as it goes) or channel (ChannelGreeter)"}
+	fileParam   = registry.Param{Name: "file", Kind: registry.String, Default: "", Description: "roster file for the file source, in any codec format"}
+	sizeParam   = registry.Param{Name: "size", Kind: registry.Int, Default: "2", Description: "salutations on each page"}
+	cursorParam = registry.Param{Name: "cursor", Kind: registry.String, Default: "", Description: "cursor to start from - empty starts at the beginning"}
+	pagesParam  = registry.Param{Name: "pages", Kind: registry.Int, Default: "0", Description: "most pages to print - zero prints them all"}
+)
+
+// Register the paging demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "paging.Pages",
+		Description: "split a roster into pages, following the cursor from each page to the next",
+		Params:      []registry.Param{sourceParam, registry.RosterParam, fileParam, sizeParam, cursorParam, pagesParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			var source Source
+			switch args.String("source") {
+			case "slice":
+				roster, err := store.LoadRoster(args.String("roster"), core.VendSalutations())
+				if err != nil {
+					return err
+				}
+				source = NewSliceSource(roster)
+			case "file":
+				if args.String("file") == "" {
+					return fmt.Errorf("%w: the file source needs -file", registry.ErrInvalidParam)
+				}
+				source = FileSource{Path: args.String("file")}
+			case "channel":
+				source = ChannelSource{Salutations: goInterfaces.VendSalutations()}
+			default:
+				return fmt.Errorf("%w: source must be slice, file or channel, got %q", registry.ErrInvalidParam, args.String("source"))
+			}
+			if args.Int("size") < 1 {
+				return fmt.Errorf("%w: size must be at least 1", registry.ErrInvalidParam)
+			}
+
+			cursor := Cursor(args.String("cursor"))
+			for number := 1; args.Int("pages") == 0 || number <= args.Int("pages"); number++ {
+				page, err := ReadPage(context.Background(), source, cursor, args.Int("size"))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "page %d:\n", number)
+				for _, salutation := range page.Salutations {
+					fmt.Fprintf(w, "  %s\n", salutation.Message(false))
+				}
+				if page.Next == "" {
+					break
+				}
+				fmt.Fprintf(w, "  next cursor: %s\n", page.Next)
+				cursor = page.Next
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,145 @@
+package paging
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/annicaburns/learngo/codec"
+	"github.com/annicaburns/learngo/core"
+)
+
+// FileSource reads a roster file in file order, decoding one salutation at a time, so a page never needs
+// more of the file in memory than the salutations on it. A cursor holds the codec.Mark after the last salutation
+// read, so the next page starts with a seek rather than by decoding every page before it again. It also records
+// the file's size and modification time - if the file changes, carrying on from the cursor returns ErrStaleCursor
+type FileSource struct {
+	Path  string
+	Codec codec.Codec // the file's format - picked from the extension of Path if nil
+}
+
+// Iterate opens the file and moves to cursor - by seeking to its mark, or for a codec from outside
+// package codec, which can't resume, by skipping the salutations before it
+func (source FileSource) Iterate(ctx context.Context, cursor Cursor) (Iterator, error) {
+	state, err := decodeCursor(cursor, "file")
+	if err != nil {
+		return nil, err
+	}
+	format := source.Codec
+	if format == nil {
+		if format, err = codec.ForPath(source.Path); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.Open(source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("paging: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("paging: %w", err)
+	}
+	if cursor != "" && (state.Size != info.Size() || state.Modified != info.ModTime().UnixNano()) {
+		file.Close()
+		return nil, fmt.Errorf("%w: %s", ErrStaleCursor, source.Path)
+	}
+	state.Size, state.Modified = info.Size(), info.ModTime().UnixNano()
+
+	it := &fileIterator{ctx: ctx, file: file, state: state}
+	if state.Offset > 0 {
+		mark := codec.Mark{Offset: state.Offset, Records: state.Count, Lines: state.Lines, State: state.State}
+		if it.decoder, err = codec.Resume(format, file, mark); err != nil {
+			file.Close()
+			if errors.Is(err, codec.ErrBadMark) {
+				err = fmt.Errorf("%w for %s: %w", ErrBadCursor, source.Path, err)
+			}
+			return nil, err
+		}
+		return it, nil
+	}
+	it.decoder = format.NewDecoder(bufio.NewReader(file))
+	for skip := state.Count; skip > 0; skip-- {
+		// a record that couldn't be decoded was still counted, as ReadPage carries on past it
+		var decodeError *codec.DecodeError
+		if _, err := it.decoder.Decode(); err != nil && !errors.As(err, &decodeError) {
+			file.Close()
+			if err == io.EOF {
+				err = fmt.Errorf("%w: %s has fewer salutations than the cursor has read", ErrStaleCursor, source.Path)
+			}
+			return nil, err
+		}
+	}
+	return it, nil
+}
+
+type fileIterator struct {
+	ctx     context.Context
+	file    *os.File
+	decoder codec.Decoder
+	current core.Salutation
+	state   cursorState
+	err     error
+	done    bool
+}
+
+func (it *fileIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err, it.done = err, true
+		return false
+	}
+	salutation, err := it.decoder.Decode()
+	if err == io.EOF {
+		it.done = true
+		return false
+	}
+	if err != nil {
+		it.err, it.done = err, true
+		// Next still stops at a record that can't be decoded, but the cursor moves past it so the next page
+		// can carry on. A decoder that has lost its place returns the same error again, and the cursor stays put
+		var decodeError *codec.DecodeError
+		if errors.As(err, &decodeError) && decodeError.Record > 0 {
+			before := it.state
+			it.advance()
+			if _, again := it.decoder.Decode(); again == err {
+				it.state = before
+			}
+		}
+		return false
+	}
+	it.current = salutation
+	it.advance()
+	return true
+}
+
+// advance moves the cursor past the record Decode read last
+func (it *fileIterator) advance() {
+	it.state.Count++
+	if resumer, ok := it.decoder.(codec.Resumer); ok {
+		mark := resumer.Mark()
+		it.state.Offset, it.state.Lines, it.state.State = mark.Offset, mark.Lines, mark.State
+	}
+}
+
+func (it *fileIterator) Salutation() core.Salutation { return it.current }
+func (it *fileIterator) Cursor() Cursor              { return it.state.encode() }
+func (it *fileIterator) Err() error                  { return it.err }
+
+func (it *fileIterator) Close() error {
+	if it.file == nil {
+		return nil
+	}
+	it.done = true
+	err := it.file.Close()
+	it.file = nil
+	if err != nil {
+		return fmt.Errorf("paging: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/codec"
+	"github.com/annicaburns/learngo/core"
+)
+
+var roster = core.Salutations{
+	{Name: "Annica", CasualGreeting: "Howdy", Prefix: "Ms "},
+	{Name: "Mitchel", CasualGreeting: "Hey,\nyou"},
+	{Name: "Marisol", FormalGreeting: "Good day"},
+	{Name: "Bob"},
+	{Name: "Joline", Locale: "en"},
+}
+
+// writeRoster writes data to a file in a temporary directory
+func writeRoster(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readAll follows the cursors from the start to the end of source, size salutations at a time
+func readAll(t *testing.T, source Source, size int) (all core.Salutations, pages int) {
+	t.Helper()
+	var cursor Cursor
+	for {
+		page, err := ReadPage(context.Background(), source, cursor, size)
+		if err != nil {
+			t.Fatalf("ReadPage after %d pages = %v", pages, err)
+		}
+		all = append(all, page.Salutations...)
+		pages++
+		if page.Next == "" {
+			return all, pages
+		}
+		cursor = page.Next
+	}
+}
+
+func TestFileSourcePagesThroughEveryFormat(t *testing.T) {
+	for _, name := range codec.Names() {
+		format, _ := codec.Lookup(name)
+		data, err := codec.MarshalAll(format, roster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := writeRoster(t, "roster."+name, data)
+		for size := 1; size <= len(roster)+1; size++ {
+			all, pages := readAll(t, FileSource{Path: path, Codec: format}, size)
+			if !slices.Equal(all, roster) {
+				t.Errorf("%s in pages of %d read %q, want %q", name, size, all, roster)
+			}
+			if wantPages := max(1, (len(roster)+size-1)/size); pages != wantPages {
+				t.Errorf("%s in pages of %d took %d pages, want %d", name, size, pages, wantPages)
+			}
+		}
+	}
+}
+
+func TestFileSourceSeeksRatherThanDecodingAgain(t *testing.T) {
+	data, _ := codec.MarshalAll(codec.NDJSON, roster)
+	path := writeRoster(t, "roster.ndjson", data)
+	source := FileSource{Path: path}
+	page, err := ReadPage(context.Background(), source, "", 2)
+	if err != nil || page.Next == "" {
+		t.Fatalf("ReadPage = %+v, %v", page, err)
+	}
+	// spoil the first line without changing the file's size or modification time - a cursor that
+	// decoded its way back to where it was would trip over it
+	info, _ := os.Stat(path)
+	spoiled := slices.Clone(data)
+	spoiled[0] = '!'
+	if err := os.WriteFile(path, spoiled, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Time{}, info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	page, err = ReadPage(context.Background(), source, page.Next, 2)
+	if err != nil || !slices.Equal(page.Salutations, roster[2:4]) {
+		t.Errorf("the second page = %q, %v, want %q", page.Salutations, err, roster[2:4])
+	}
+}
+
+func TestFileSourceStaleCursor(t *testing.T) {
+	data, _ := codec.MarshalAll(codec.CSV, roster)
+	path := writeRoster(t, "roster.csv", data)
+	source := FileSource{Path: path}
+	page, err := ReadPage(context.Background(), source, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, "Cara\n"...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadPage(context.Background(), source, page.Next, 2); !errors.Is(err, ErrStaleCursor) {
+		t.Errorf("ReadPage after the file changed = %v, want ErrStaleCursor", err)
+	}
+	// a cursor from another kind of source, or one that has been tampered with, is no good either
+	for _, cursor := range []Cursor{"not base64!", (cursorState{Kind: "slice"}).encode(), (cursorState{Kind: "file", Offset: -5}).encode()} {
+		if _, err := ReadPage(context.Background(), source, cursor, 2); !errors.Is(err, ErrBadCursor) {
+			t.Errorf("ReadPage(%q) = %v, want ErrBadCursor", cursor, err)
+		}
+	}
+}
+
+// outsideCodec hides a codec's ability to resume, like a Codec from outside package codec
+type outsideCodec struct {
+	codec.Codec
+}
+
+func (format outsideCodec) NewDecoder(r io.Reader) codec.Decoder {
+	return struct{ codec.Decoder }{format.Codec.NewDecoder(r)}
+}
+
+func TestReadPageCarriesOnPastABadRecord(t *testing.T) {
+	// the third record of each file can't be decoded, but the records around it can
+	files := map[string]string{
+		"roster.yaml":   "- name: Annica\n- name: Bob\n- name: Mitchel\n  nickname: Mitch\n- name: Joline\n",
+		"roster.ndjson": `{"name":"Annica"}` + "\n" + `{"name":"Bob"}` + "\n" + `{"name":"Mitchel","nickname":"Mitch"}` + "\n" + `{"name":"Joline"}` + "\n",
+		"roster.json":   `[{"name":"Annica"},{"name":"Bob"},{"name":"Mitchel","nickname":"Mitch"},{"name":"Joline"}]`,
+		"roster.csv":    "name\nAnnica\nBob\nMitchel,Mitch\nJoline\n",
+		"roster.xml":    `<salutations><salutation name="Annica"/><salutation name="Bob"/><salutation name="Mitchel"><nickname>Mitch</nickname></salutation><salutation name="Joline"/></salutations>`,
+	}
+	want := core.Salutations{{Name: "Annica"}, {Name: "Bob"}, {Name: "Joline"}}
+	for name, data := range files {
+		path := writeRoster(t, name, []byte(data))
+		format, _ := codec.ForPath(path)
+		for _, source := range []FileSource{{Path: path}, {Path: path, Codec: outsideCodec{format}}} {
+			// a full page before the bad record hands out a cursor, so the error is seen
+			page, err := ReadPage(context.Background(), source, "", 2)
+			if err != nil || !slices.Equal(page.Salutations, want[:2]) || page.Next == "" {
+				t.Fatalf("%s: ReadPage = %+v, %v, want Annica and Bob and a cursor", name, page, err)
+			}
+			// the page with the bad record on it keeps the salutations before it, and its cursor skips it
+			var decodeError *codec.DecodeError
+			for _, start := range []Cursor{"", page.Next} {
+				page, err = ReadPage(context.Background(), source, start, 3)
+				if !errors.As(err, &decodeError) || decodeError.Record != 3 {
+					t.Fatalf("%s: ReadPage(%q) = %v, want the error in record 3", name, start, err)
+				}
+				if name == "roster.yaml" && decodeError.Line != 4 {
+					t.Errorf("the error is on line %d, want 4", decodeError.Line)
+				}
+				if start == "" && !slices.Equal(page.Salutations, want[:2]) {
+					t.Errorf("%s: the page with the bad record on it holds %q, want Annica and Bob", name, page.Salutations)
+				}
+				page, err = ReadPage(context.Background(), source, page.Next, 3)
+				if err != nil || !slices.Equal(page.Salutations, want[2:]) || page.Next != "" {
+					t.Errorf("%s: the page after the bad record = %+v, %v, want Joline", name, page, err)
+				}
+			}
+		}
+	}
+}
+
+func TestReadPageStopsWhereTheDecoderLostItsPlace(t *testing.T) {
+	path := writeRoster(t, "roster.xml", []byte(`<salutations><salutation name="Annica"/><salutation name="Bob"></oops><salutation name="Joline"/></salutations>`))
+	source := FileSource{Path: path}
+	page, err := ReadPage(context.Background(), source, "", 3)
+	var decodeError *codec.DecodeError
+	if !errors.As(err, &decodeError) || !slices.Equal(page.Salutations, core.Salutations{{Name: "Annica"}}) {
+		t.Fatalf("ReadPage = %+v, %v, want Annica and a decode error", page, err)
+	}
+	// there is no telling where the next record starts, so the cursor tries the same place again
+	if again, err := ReadPage(context.Background(), source, page.Next, 3); err == nil || len(again.Salutations) != 0 {
+		t.Errorf("ReadPage from the cursor = %+v, %v, want the error again", again, err)
+	}
+}
@@ -0,0 +1,145 @@
+package paging
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// goLoops.CollectionLoop and goInterfaces.Salutations.greet need the whole roster in a slice before they start.
+// An Iterator hands out one salutation at a time instead, from wherever the roster lives:
+//   SliceSource   - a slice in memory, in name order
+//   FileSource    - a roster file in any codec format, in file order, read as it goes
+//   ChannelSource - the goInterfaces.ChannelGreeter producer, in the order it sends
+// Every Iterator can say where it has got to as a Cursor - an opaque string that can be handed to a client
+// and given back later to carry on from the same place, which is how ReadPage splits a roster into pages.
+// The order is stable, so following the cursors visits every salutation once.
+// Values and All turn an Iterator into an iter.Seq for a range loop.
+
+// Errors returned for a Cursor that can't be used. Use errors.Is to check for them
+var (
+	ErrBadCursor   = errors.New("paging: bad cursor")
+	ErrStaleCursor = errors.New("paging: the roster has changed since the cursor was made")
+)
+
+// Cursor is an opaque position in a Source. The empty Cursor is the start
+type Cursor string
+
+// Iterator reads salutations one at a time:
+//
+//	for it.Next() {
+//		use(it.Salutation())
+//	}
+//	if err := it.Err(); err != nil {...}
+//	it.Close()
+type Iterator interface {
+	Next() bool                  // moves to the next salutation - false at the end or after an error
+	Salutation() core.Salutation // the salutation Next moved to
+	Cursor() Cursor              // the position after the current salutation, or after a bad record Next stopped at
+	Err() error                  // what stopped Next, or nil if the end was reached
+	Close() error                // releases the source - it is safe to call more than once
+}
+
+// Source is somewhere salutations can be read from, starting at any cursor it has handed out
+type Source interface {
+	Iterate(ctx context.Context, cursor Cursor) (Iterator, error)
+}
+
+// Page is one page of salutations
+type Page struct {
+	Salutations core.Salutations
+	Next        Cursor // where the next page starts - empty after the last page
+}
+
+// ReadPage reads at most size salutations from source, starting at cursor.
+// If reading fails part way it returns the salutations before the failure along with the error. Next then
+// carries on past a record that couldn't be decoded, or from the failure itself for anything else
+func ReadPage(ctx context.Context, source Source, cursor Cursor, size int) (page Page, err error) {
+	if size < 1 {
+		return page, fmt.Errorf("paging: page size must be at least 1, got %d", size)
+	}
+	it, err := source.Iterate(ctx, cursor)
+	if err != nil {
+		return page, err
+	}
+	defer it.Close()
+	page.Salutations = make(core.Salutations, 0, size)
+	var next Cursor
+	for len(page.Salutations) < size && it.Next() {
+		page.Salutations = append(page.Salutations, it.Salutation())
+		next = it.Cursor()
+	}
+	if err := it.Err(); err != nil {
+		page.Next = it.Cursor()
+		return page, err
+	}
+	// only hand out a cursor if there is something after it. If that something can't be read the page
+	// is still complete, and reading from the cursor returns the error
+	if len(page.Salutations) == size && (it.Next() || it.Err() != nil) {
+		page.Next = next
+	}
+	return page, it.Close()
+}
+
+// Values returns the salutations from it for a range loop, and closes it when the loop ends.
+// Check it.Err afterwards - the loop stops early on an error
+func Values(it Iterator) iter.Seq[core.Salutation] {
+	return func(yield func(core.Salutation) bool) {
+		defer it.Close()
+		for it.Next() {
+			if !yield(it.Salutation()) {
+				return
+			}
+		}
+	}
+}
+
+// All is Values with the cursor after each salutation, so a loop can remember where to carry on from
+func All(it Iterator) iter.Seq2[Cursor, core.Salutation] {
+	return func(yield func(Cursor, core.Salutation) bool) {
+		defer it.Close()
+		for it.Next() {
+			if !yield(it.Cursor(), it.Salutation()) {
+				return
+			}
+		}
+	}
+}
+
+// cursorState is what a Cursor holds. Each source uses the fields it needs
+type cursorState struct {
+	Kind     string `json:"k"`
+	After    string `json:"a,omitempty"` // SliceSource: the name of the last salutation
+	Skip     int    `json:"s,omitempty"` // SliceSource: how many salutations with that name have been read
+	Count    int    `json:"n,omitempty"` // FileSource and ChannelSource: how many salutations have been read
+	Offset   int64  `json:"o,omitempty"` // FileSource: the codec.Mark to carry on from
+	Lines    int    `json:"l,omitempty"`
+	State    string `json:"x,omitempty"`
+	Size     int64  `json:"z,omitempty"` // FileSource: the size of the file
+	Modified int64  `json:"m,omitempty"` // FileSource: when the file was last changed, in Unix nanoseconds
+}
+
+func (state cursorState) encode() Cursor {
+	data, _ := json.Marshal(state)
+	return Cursor(base64.RawURLEncoding.EncodeToString(data))
+}
+
+// decodeCursor reads a cursor that must have been made by a source of the kind given
+func decodeCursor(cursor Cursor, kind string) (state cursorState, err error) {
+	if cursor == "" {
+		return cursorState{Kind: kind}, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(string(cursor))
+	if err == nil {
+		err = json.Unmarshal(data, &state)
+	}
+	if err != nil || state.Kind != kind || state.Skip < 0 || state.Count < 0 || state.Offset < 0 || state.Lines < 0 {
+		return state, fmt.Errorf("%w for a %s source", ErrBadCursor, kind)
+	}
+	return state, nil
+}
@@ -0,0 +1,84 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/goInterfaces"
+)
+
+// closeCounter counts the calls to Close
+type closeCounter struct {
+	Iterator
+	closes int
+}
+
+func (it *closeCounter) Close() error {
+	it.closes++
+	return it.Iterator.Close()
+}
+
+func TestValuesAndAllCloseWhenTheLoopBreaks(t *testing.T) {
+	checkNoLeaks(t)
+	source := ChannelSource{Salutations: goInterfaces.VendSalutations()}
+	want := core.FromSalutations(source.Salutations)
+	iterate := func() *closeCounter {
+		it, err := source.Iterate(context.Background(), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &closeCounter{Iterator: it}
+	}
+
+	it := iterate()
+	var names []string
+	for salutation := range Values(it) {
+		names = append(names, salutation.Name)
+		if len(names) == 2 {
+			break
+		}
+	}
+	if !slices.Equal(names, []string{want[0].Name, want[1].Name}) || it.closes != 1 {
+		t.Errorf("Values read %q and closed %d times", names, it.closes)
+	}
+
+	it = iterate()
+	var cursor Cursor
+	for after, salutation := range All(it) {
+		cursor = after
+		if salutation.Name == want[0].Name {
+			break
+		}
+	}
+	if it.closes != 1 {
+		t.Errorf("All closed %d times", it.closes)
+	}
+	// the cursor All gave for the salutation the loop stopped at carries on after it
+	page, err := ReadPage(context.Background(), source, cursor, len(want))
+	if err != nil || !slices.Equal(page.Salutations, want[1:]) {
+		t.Errorf("carrying on from All's cursor read %q, %v, want %q", page.Salutations, err, want[1:])
+	}
+
+	it = iterate()
+	var all core.Salutations
+	for salutation := range Values(it) {
+		all = append(all, salutation)
+	}
+	if !slices.Equal(all, want) || it.closes != 1 || it.Err() != nil {
+		t.Errorf("Values to the end read %q, closed %d times, and Err = %v", all, it.closes, it.Err())
+	}
+}
+
+func TestReadPageRejectsASmallSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		if _, err := ReadPage(context.Background(), NewSliceSource(bobs), "", size); err == nil {
+			t.Errorf("ReadPage with size %d succeeded", size)
+		}
+	}
+	if _, err := ReadPage(context.Background(), NewSliceSource(bobs), (cursorState{Kind: "channel"}).encode(), 1); !errors.Is(err, ErrBadCursor) {
+		t.Errorf("ReadPage with a channel cursor = %v, want ErrBadCursor", err)
+	}
+}
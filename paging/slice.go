@@ -0,0 +1,68 @@
+package paging
+
+import (
+	"context"
+	"sort"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// SliceSource reads a slice of salutations in name order. Salutations with the same name keep their order in the slice.
+// A cursor remembers the last name read rather than an index, so it still works with a SliceSource
+// made later from a roster that has gained or lost salutations
+type SliceSource struct {
+	sorted core.Salutations
+}
+
+// NewSliceSource creates a SliceSource from a copy of salutations
+func NewSliceSource(salutations core.Salutations) *SliceSource {
+	sorted := append(core.Salutations(nil), salutations...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return &SliceSource{sorted: sorted}
+}
+
+// Iterate starts after cursor. ctx isn't used - reading a slice never blocks
+func (source *SliceSource) Iterate(ctx context.Context, cursor Cursor) (Iterator, error) {
+	state, err := decodeCursor(cursor, "slice")
+	if err != nil {
+		return nil, err
+	}
+	start := 0
+	if cursor != "" {
+		// the first salutation named After, moved on by the Skip of them already read - but never past the last of them
+		first := sort.Search(len(source.sorted), func(i int) bool { return source.sorted[i].Name >= state.After })
+		end := sort.Search(len(source.sorted), func(i int) bool { return source.sorted[i].Name > state.After })
+		start = min(first+state.Skip, end)
+	}
+	return &sliceIterator{sorted: source.sorted, next: start, state: state}, nil
+}
+
+type sliceIterator struct {
+	sorted  core.Salutations
+	next    int
+	current core.Salutation
+	state   cursorState
+}
+
+func (it *sliceIterator) Next() bool {
+	if it.next >= len(it.sorted) {
+		return false
+	}
+	it.current = it.sorted[it.next]
+	it.next++
+	if it.current.Name == it.state.After {
+		it.state.Skip++
+	} else {
+		it.state.After, it.state.Skip = it.current.Name, 1
+	}
+	return true
+}
+
+func (it *sliceIterator) Salutation() core.Salutation { return it.current }
+func (it *sliceIterator) Cursor() Cursor              { return it.state.encode() }
+func (it *sliceIterator) Err() error                  { return nil }
+
+func (it *sliceIterator) Close() error {
+	it.next = len(it.sorted)
+	return nil
+}
@@ -0,0 +1,68 @@
+package paging
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// bobs has salutations with the same name, which only their greetings tell apart
+var bobs = core.Salutations{
+	{Name: "Dan"},
+	{Name: "Bob", CasualGreeting: "first"},
+	{Name: "Annica"},
+	{Name: "Bob", CasualGreeting: "second"},
+	{Name: "Cara"},
+}
+
+func TestSliceSourcePagesInNameOrder(t *testing.T) {
+	want := core.Salutations{bobs[2], bobs[1], bobs[3], bobs[4], bobs[0]}
+	for size := 1; size <= len(bobs)+1; size++ {
+		if all, _ := readAll(t, NewSliceSource(bobs), size); !slices.Equal(all, want) {
+			t.Errorf("in pages of %d read %q, want %q", size, all, want)
+		}
+	}
+	// the source has its own copy
+	roster := slices.Clone(bobs)
+	source := NewSliceSource(roster)
+	roster[0].Name = "Zed"
+	if all, _ := readAll(t, source, 2); !slices.Equal(all, want) {
+		t.Errorf("after the roster changed the source read %q", all)
+	}
+}
+
+func TestSliceCursorSurvivesAChangedRoster(t *testing.T) {
+	// the first page ends part way through the Bobs
+	page, err := ReadPage(context.Background(), NewSliceSource(bobs), "", 2)
+	if err != nil || page.Next == "" {
+		t.Fatalf("ReadPage = %+v, %v", page, err)
+	}
+	tests := []struct {
+		label  string
+		roster core.Salutations
+		want   []string // the greetings, or names for salutations without one, read from the cursor on
+	}{
+		{"unchanged", bobs, []string{"second", "Cara", "Dan"}},
+		{"names added before and after", append(slices.Clone(bobs), core.Salutation{Name: "Aaron"}, core.Salutation{Name: "Bea"}, core.Salutation{Name: "Carl"}), []string{"second", "Cara", "Carl", "Dan"}},
+		{"another Bob added", append(slices.Clone(bobs), core.Salutation{Name: "Bob", CasualGreeting: "third"}), []string{"second", "third", "Cara", "Dan"}},
+		{"every Bob deleted", core.Salutations{bobs[0], bobs[2], bobs[4]}, []string{"Cara", "Dan"}},
+		{"everything after deleted", core.Salutations{bobs[1], bobs[2]}, nil},
+	}
+	for _, test := range tests {
+		rest, err := ReadPage(context.Background(), NewSliceSource(test.roster), page.Next, 10)
+		var got []string
+		for _, salutation := range rest.Salutations {
+			got = append(got, cmp.Or(salutation.CasualGreeting, salutation.Name))
+		}
+		if err != nil || !slices.Equal(got, test.want) || rest.Next != "" {
+			t.Errorf("%s: carrying on read %q, %v, want %q", test.label, got, err, test.want)
+		}
+	}
+	if _, err := NewSliceSource(bobs).Iterate(context.Background(), (cursorState{Kind: "slice", Skip: -1}).encode()); !errors.Is(err, ErrBadCursor) {
+		t.Errorf("Iterate with a negative skip = %v, want ErrBadCursor", err)
+	}
+}
@@ -0,0 +1,187 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// The registry is a shared lookup table of every runnable demo in learngo.
+// Each demo package adds its own entries from an init function, so importing a package is enough to make its demos available.
+// Names follow the "package.Function" convention - for example "goConcurrency.ChannelWithRange"
+// Tooling (the learngo command, tests, a web UI) can then List the demos, read their parameter schema and Run them uniformly.
+
+// Kind is the type of value a parameter accepts
+type Kind int
+
+// The kinds of parameter a demo can accept
+const (
+	String Kind = iota
+	Int
+	Bool
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case String:
+		return "string"
+	case Int:
+		return "int"
+	case Bool:
+		return "bool"
+	default:
+		return "Kind(" + strconv.Itoa(int(kind)) + ")"
+	}
+}
+
+// Param describes a single parameter a demo accepts. Default is written the same way a caller would supply the value
+type Param struct {
+	Name        string
+	Kind        Kind
+	Default     string
+	Description string
+}
+
+// The parameters shared by most of the demos
+var (
+	NameParam     = Param{Name: "name", Kind: String, Default: "Annica", Description: "name of the person to greet"}
+	GreetingParam = Param{Name: "greeting", Kind: String, Default: "Dearest", Description: "greeting to use"}
+	TimesParam    = Param{Name: "times", Kind: Int, Default: "3", Description: "number of times to repeat the greeting"}
+	IsFormalParam = Param{Name: "isFormal", Kind: Bool, Default: "false", Description: "use the formal greeting"}
+	RosterParam   = Param{Name: "roster", Kind: String, Default: "", Description: "roster file (.json or .kv) to use instead of the built in salutations"}
+)
+
+// Args holds the validated parameter values a demo is run with - every parameter in the demo's schema is present
+type Args map[string]string
+
+// String returns the value of a string parameter
+func (args Args) String(name string) string {
+	return args[name]
+}
+
+// Int returns the value of an int parameter. Run has already checked the value parses
+func (args Args) Int(name string) (value int) {
+	value, _ = strconv.Atoi(args[name])
+	return
+}
+
+// Bool returns the value of a bool parameter. Run has already checked the value parses
+func (args Args) Bool(name string) (value bool) {
+	value, _ = strconv.ParseBool(args[name])
+	return
+}
+
+// Demo is a single runnable example
+type Demo struct {
+	Name        string
+	Description string
+	Params      []Param
+	Run         func(w io.Writer, args Args) error
+}
+
+// Errors returned by Run. Use errors.Is to check for them
+var (
+	ErrUnknownDemo  = errors.New("unknown demo")
+	ErrInvalidParam = errors.New("invalid parameter")
+)
+
+var (
+	mutex sync.RWMutex
+	demos = make(map[string]Demo)
+)
+
+// Register adds a demo to the registry. It is called from init functions, so mistakes are programming errors and panic
+func Register(demo Demo) {
+	if demo.Name == "" || demo.Run == nil {
+		panic("registry: demo needs a name and a run function")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if _, exists := demos[demo.Name]; exists {
+		panic(fmt.Sprintf("registry: demo %q registered twice", demo.Name))
+	}
+	demos[demo.Name] = demo
+}
+
+// Lookup returns the demo registered under name and whether it exists
+func Lookup(name string) (demo Demo, exists bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	demo, exists = demos[name]
+	return
+}
+
+// List returns every registered demo in alphabetical order by name
+func List() (list []Demo) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	for _, demo := range demos {
+		list = append(list, demo)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return
+}
+
+// Names returns the name of every registered demo in alphabetical order
+func Names() (names []string) {
+	for _, demo := range List() {
+		names = append(names, demo.Name)
+	}
+	return
+}
+
+// Args checks values against the demo's parameter schema and fills in defaults for anything missing
+func (demo Demo) Args(values map[string]string) (args Args, err error) {
+	args = make(Args, len(demo.Params))
+	for _, param := range demo.Params {
+		value, supplied := values[param.Name]
+		if !supplied {
+			value = param.Default
+		}
+		if err = param.check(value); err != nil {
+			return nil, err
+		}
+		args[param.Name] = value
+	}
+	for name := range values {
+		if _, known := args[name]; !known {
+			return nil, fmt.Errorf("%w: %s does not take %q", ErrInvalidParam, demo.Name, name)
+		}
+	}
+	return
+}
+
+func (param Param) check(value string) (err error) {
+	switch param.Kind {
+	case Int:
+		_, err = strconv.Atoi(value)
+	case Bool:
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s must be %s, got %q", ErrInvalidParam, param.Name, param.Kind, value)
+	}
+	return nil
+}
+
+// Run looks up a demo by name, validates values against its schema and runs it, writing the output to w.
+// A demo that panics is reported as an error rather than bringing the caller down with it
+func Run(name string, w io.Writer, values map[string]string) (err error) {
+	demo, exists := Lookup(name)
+	if !exists {
+		return fmt.Errorf("%w %q", ErrUnknownDemo, name)
+	}
+	args, err := demo.Args(values)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s panicked: %v", name, r)
+		}
+	}()
+	return demo.Run(w, args)
+}
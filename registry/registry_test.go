@@ -0,0 +1,132 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// record is a demo that writes back every parameter it was given
+func record(name string, params ...Param) Demo {
+	return Demo{Name: name, Params: params, Run: func(w io.Writer, args Args) error {
+		for _, param := range params {
+			fmt.Fprintf(w, "%s=%s ", param.Name, args[param.Name])
+		}
+		return nil
+	}}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	Register(record("test.Twice"))
+	tests := map[string]Demo{
+		"a duplicate name": record("test.Twice"),
+		"no name":          record(""),
+		"no run function":  {Name: "test.NoRun"},
+	}
+	for label, demo := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register with %s didn't panic", label)
+				}
+			}()
+			Register(demo)
+		}()
+	}
+}
+
+func TestLookupAndList(t *testing.T) {
+	for _, name := range []string{"order.b", "order.c", "order.a"} {
+		Register(record(name))
+	}
+	if demo, exists := Lookup("order.c"); !exists || demo.Name != "order.c" {
+		t.Errorf("Lookup(order.c) = %v, %t", demo.Name, exists)
+	}
+	if _, exists := Lookup("order.z"); exists {
+		t.Error("Lookup found a demo that was never registered")
+	}
+	names := Names()
+	if !slices.IsSorted(names) {
+		t.Errorf("Names isn't in order: %q", names)
+	}
+	var ours []string
+	for _, demo := range List() {
+		if strings.HasPrefix(demo.Name, "order.") {
+			ours = append(ours, demo.Name)
+		}
+	}
+	if want := []string{"order.a", "order.b", "order.c"}; !slices.Equal(ours, want) {
+		t.Errorf("List gave %q, want %q", ours, want)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	demo := record("test.Args", NameParam, TimesParam, IsFormalParam)
+	tests := []struct {
+		values map[string]string
+		want   Args
+		err    string
+	}{
+		{nil, Args{"name": "Annica", "times": "3", "isFormal": "false"}, ""},
+		{map[string]string{"name": "", "times": "-2", "isFormal": "T"}, Args{"name": "", "times": "-2", "isFormal": "T"}, ""},
+		{map[string]string{"times": "2.5"}, nil, `times must be int, got "2.5"`},
+		{map[string]string{"times": ""}, nil, `times must be int, got ""`},
+		{map[string]string{"isFormal": "yes"}, nil, `isFormal must be bool, got "yes"`},
+		{map[string]string{"nickname": "Bob"}, nil, `test.Args does not take "nickname"`},
+	}
+	for _, test := range tests {
+		args, err := demo.Args(test.values)
+		if test.err != "" {
+			if !errors.Is(err, ErrInvalidParam) || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Args(%v) = %v, want ErrInvalidParam saying %s", test.values, err, test.err)
+			}
+			continue
+		}
+		if err != nil || len(args) != len(test.want) {
+			t.Errorf("Args(%v) = %v, %v, want %v", test.values, args, err, test.want)
+			continue
+		}
+		for name, value := range test.want {
+			if args[name] != value {
+				t.Errorf("Args(%v)[%s] = %q, want %q", test.values, name, args[name], value)
+			}
+		}
+	}
+	args, _ := demo.Args(map[string]string{"times": "7", "isFormal": "1"})
+	if args.String("name") != "Annica" || args.Int("times") != 7 || !args.Bool("isFormal") {
+		t.Errorf("Args gave %v", args)
+	}
+}
+
+func TestKindString(t *testing.T) {
+	for kind, want := range map[Kind]string{String: "string", Int: "int", Bool: "bool", Kind(9): "Kind(9)"} {
+		if kind.String() != want {
+			t.Errorf("Kind %d = %q, want %q", int(kind), kind.String(), want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	Register(record("test.Run", NameParam, TimesParam))
+	Register(Demo{Name: "test.Panic", Run: func(io.Writer, Args) error {
+		var salutations []string
+		return errors.New(salutations[3])
+	}})
+	var output strings.Builder
+	if err := Run("test.Run", &output, map[string]string{"times": "2"}); err != nil || output.String() != "name=Annica times=2 " {
+		t.Errorf("Run = %v, wrote %q", err, output.String())
+	}
+	if err := Run("test.Run", io.Discard, map[string]string{"times": "two"}); !errors.Is(err, ErrInvalidParam) {
+		t.Errorf("Run with a bad int = %v, want ErrInvalidParam", err)
+	}
+	if err := Run("test.Missing", io.Discard, nil); !errors.Is(err, ErrUnknownDemo) {
+		t.Errorf("Run of a missing demo = %v, want ErrUnknownDemo", err)
+	}
+	err := Run("test.Panic", io.Discard, nil)
+	if err == nil || !strings.Contains(err.Error(), "test.Panic panicked: runtime error: index out of range") {
+		t.Errorf("Run of a demo that panics = %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package rotation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// A calendar file has one holiday greeting to a line - a date, then the greeting:
+//
+//	# every year
+//	01-01 Happy New Year
+//	12-25 Merry Christmas
+//	# only in 2026
+//	2026-11-26 Happy Thanksgiving
+//
+// A month-day date applies every year and a full date only to that day. A date can have several lines,
+// and all of their greetings are candidates. Blank lines and lines starting with # are ignored.
+
+// ErrBadDate is returned for a calendar date that isn't MM-DD or YYYY-MM-DD
+var ErrBadDate = errors.New("rotation: bad date")
+
+// Calendar holds the holiday greetings for each date. It is safe to use from several goroutines
+type Calendar struct {
+	mutex     sync.RWMutex
+	greetings map[string][]string // keyed by "01-02" for every year or "2006-01-02" for a single day
+}
+
+// NewCalendar creates a calendar without any holidays
+func NewCalendar() *Calendar {
+	return &Calendar{greetings: make(map[string][]string)}
+}
+
+// parseDate checks date and returns it in the form Calendar uses as a key
+func parseDate(date string) (string, error) {
+	layout := "01-02"
+	if len(date) > len(layout) {
+		layout = "2006-01-02"
+	}
+	// a month-day is parsed in year 0, a leap year, so 02-29 is allowed
+	parsed, err := time.Parse(layout, date)
+	if err != nil {
+		return "", fmt.Errorf("%w %q - use MM-DD for every year or YYYY-MM-DD for one day", ErrBadDate, date)
+	}
+	return parsed.Format(layout), nil
+}
+
+// Add adds greetings for a date, written MM-DD for every year or YYYY-MM-DD for a single day
+func (calendar *Calendar) Add(date string, greetings ...string) error {
+	key, err := parseDate(date)
+	if err != nil {
+		return err
+	}
+	calendar.mutex.Lock()
+	defer calendar.mutex.Unlock()
+	calendar.greetings[key] = append(calendar.greetings[key], greetings...)
+	return nil
+}
+
+// Greetings returns the holiday greetings for t's date in t's location - those for that single day first,
+// then those for every year. It returns nil when t isn't a holiday
+func (calendar *Calendar) Greetings(t time.Time) []string {
+	calendar.mutex.RLock()
+	defer calendar.mutex.RUnlock()
+	var greetings []string
+	greetings = append(greetings, calendar.greetings[t.Format("2006-01-02")]...)
+	greetings = append(greetings, calendar.greetings[t.Format("01-02")]...)
+	return greetings
+}
+
+// ParseCalendar reads a calendar file. An error names the line it is on
+func ParseCalendar(r io.Reader) (*Calendar, error) {
+	calendar := NewCalendar()
+	lines := bufio.NewScanner(r)
+	for number := 1; lines.Scan(); number++ {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		date, greeting := line, ""
+		if space := strings.IndexFunc(line, unicode.IsSpace); space >= 0 {
+			date, greeting = line[:space], strings.TrimSpace(line[space:])
+		}
+		if greeting == "" {
+			return nil, fmt.Errorf("rotation: calendar line %d: %q has a date but no greeting", number, line)
+		}
+		if err := calendar.Add(date, greeting); err != nil {
+			return nil, fmt.Errorf("rotation: calendar line %d: %w", number, err)
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("rotation: %w", err)
+	}
+	return calendar, nil
+}
+
+// LoadCalendar reads the calendar file at path
+func LoadCalendar(path string) (*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("rotation: %w", err)
+	}
+	defer file.Close()
+	calendar, err := ParseCalendar(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return calendar, nil
+}
@@ -0,0 +1,71 @@
+package rotation
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/annicaburns/learngo/clock"
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/greeting"
+	"github.com/annicaburns/learngo/registry"
+)
+
+// Parameters only the rotation demos use
+var (
+	startParam    = registry.Param{Name: "start", Kind: registry.String, Default: "2026-12-24T07:00", Description: "time of the first greeting, YYYY-MM-DDTHH:MM"}
+	stepParam     = registry.Param{Name: "step", Kind: registry.String, Default: "4h", Description: "time between greetings, such as 90m"}
+	windowParam   = registry.Param{Name: "window", Kind: registry.Int, Default: "2", Description: "number of recent greetings not to repeat"}
+	calendarParam = registry.Param{Name: "calendar", Kind: registry.String, Default: "", Description: "holiday calendar file - empty uses a few built in holidays"}
+	strategyParam = registry.Param{Name: "strategy", Kind: registry.String, Default: "first", Description: "first, last, random or round-robin"}
+)
+
+// demoCalendar is used when the demo isn't given a calendar file
+func demoCalendar() *Calendar {
+	calendar := NewCalendar()
+	calendar.Add("01-01", "Happy New Year")
+	calendar.Add("12-25", "Merry Christmas", "Happy holidays")
+	return calendar
+}
+
+// Register the rotation demos so they can be discovered and run by name
+func init() {
+	registry.Register(registry.Demo{
+		Name:        "rotation.Rotate",
+		Description: "vary the greeting by time of day and holiday, on a fake clock",
+		Params:      []registry.Param{registry.NameParam, registry.GreetingParam, registry.TimesParam, startParam, stepParam, windowParam, calendarParam, strategyParam},
+		Run: func(w io.Writer, args registry.Args) error {
+			start, err := time.ParseInLocation("2006-01-02T15:04", args.String("start"), time.Local)
+			if err != nil {
+				return fmt.Errorf("%w: start must look like 2026-12-24T07:00, got %q", registry.ErrInvalidParam, args.String("start"))
+			}
+			step, err := time.ParseDuration(args.String("step"))
+			if err != nil {
+				return fmt.Errorf("%w: step must be a duration such as 90m, got %q", registry.ErrInvalidParam, args.String("step"))
+			}
+			strategy, err := greeting.ParseStrategy(args.String("strategy"), 1)
+			if err != nil {
+				return fmt.Errorf("%w: %v", registry.ErrInvalidParam, err)
+			}
+			calendar := demoCalendar()
+			if path := args.String("calendar"); path != "" {
+				if calendar, err = LoadCalendar(path); err != nil {
+					return err
+				}
+			}
+			// the fake clock only moves when the loop advances it, so every run prints the same greetings
+			fake := clock.NewFake(start)
+			rotator := &Rotator{Clock: fake, Calendar: calendar, Window: args.Int("window"), Strategy: strategy}
+			salutation := core.Salutation{Name: args.String("name"), CasualGreeting: args.String("greeting")}
+			for i := 0; i < args.Int("times"); i++ {
+				message, err := rotator.Message(salutation)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "%s  %-9s  %s\n", fake.Now().Format("Mon Jan 2 15:04"), PeriodOf(fake.Now()), message)
+				fake.Advance(step)
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,237 @@
+package rotation
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/annicaburns/learngo/clock"
+	"github.com/annicaburns/learngo/core"
+	"github.com/annicaburns/learngo/greeting"
+)
+
+// goLoops.BasicForLoop says the same thing every time round the loop. A Rotator varies the greeting instead:
+//   - the candidates depend on the time of day - "Good morning" before noon, "Good evening" after five
+//   - on a holiday from the Calendar the holiday's greetings are used instead, such as "Happy New Year"
+//   - a greeting used for a name within the last Window greetings isn't used for that name again if there is another one,
+//     for up to MaxNames names - the name greeted longest ago is forgotten to make room for a new one
+//   - whatever is left is chosen by a greeting.Strategy
+// The time comes from an injected clock.Clock, so with a clock.Fake the same calls always give the same greetings.
+
+// Period is a part of the day
+type Period int
+
+// The parts of the day. Anytime is for greetings in a Pool that suit every part of it
+const (
+	Anytime   Period = iota
+	Morning          // 05:00 to 11:59
+	Afternoon        // 12:00 to 16:59
+	Evening          // 17:00 to 21:59
+	Night            // 22:00 to 04:59
+)
+
+var periodNames = []string{"anytime", "morning", "afternoon", "evening", "night"}
+
+func (period Period) String() string {
+	if period < Anytime || period > Night {
+		return "Period(" + strconv.Itoa(int(period)) + ")"
+	}
+	return periodNames[period]
+}
+
+// PeriodOf returns the part of the day t falls in, in t's location
+func PeriodOf(t time.Time) Period {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 12:
+		return Morning
+	case hour >= 12 && hour < 17:
+		return Afternoon
+	case hour >= 17 && hour < 22:
+		return Evening
+	default:
+		return Night
+	}
+}
+
+// Pool holds the greetings for each part of the day
+type Pool map[Period][]string
+
+// DefaultPool is used by a Rotator without a Pool
+var DefaultPool = Pool{
+	Morning:   {"Good morning", "Morning"},
+	Afternoon: {"Good afternoon", "Hello"},
+	Evening:   {"Good evening", "Evening"},
+	Night:     {"Good evening", "Hello"},
+}
+
+// DefaultMaxNames is how many names a Rotator without MaxNames remembers greetings for
+const DefaultMaxNames = 1000
+
+// Rotator chooses a different greeting each time it is asked. The zero Rotator uses the real clock,
+// DefaultPool, no holidays, doesn't avoid repeats and takes the first candidate - set the fields to change that.
+// The fields must not be changed once Next has been called. It is safe to use from several goroutines
+type Rotator struct {
+	Clock    clock.Clock       // where the time comes from - clock.Real if nil
+	Location *time.Location    // the time zone for the time of day and the date - the clock's own if nil
+	Pool     Pool              // greetings for each part of the day - DefaultPool if nil
+	Calendar *Calendar         // holidays - none if nil
+	Window   int               // how many of a name's latest greetings not to repeat - zero allows any repeat
+	MaxNames int               // how many names to remember greetings for - DefaultMaxNames if zero
+	Strategy greeting.Strategy // chooses from what is left - greeting.First if nil
+
+	mutex   sync.Mutex
+	history map[string]*list.Element // each name's element in recent
+	recent  *list.List               // a *nameHistory for each name, the one greeted most recently at the front
+}
+
+// nameHistory is the latest greetings for a name, oldest first
+type nameHistory struct {
+	name      string
+	greetings []string
+}
+
+// Candidates returns the greetings that suit the current time, before repeats are left out:
+// the holiday's greetings on a holiday, and otherwise the Pool's greetings for the part of the day
+// followed by its Anytime greetings and the salutation's own casual greeting
+func (rotator *Rotator) Candidates(salutation core.Salutation) []string {
+	now := rotator.now()
+	if rotator.Calendar != nil {
+		if holiday := rotator.Calendar.Greetings(now); len(holiday) > 0 {
+			return holiday
+		}
+	}
+	pool := rotator.Pool
+	if pool == nil {
+		pool = DefaultPool
+	}
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{pool[PeriodOf(now)], pool[Anytime], {salutation.Greeting(false)}} {
+		for _, candidate := range list {
+			if candidate != "" && !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
+
+// Next chooses the greeting for salutation and remembers it, so it isn't repeated within the Window.
+// It returns greeting.ErrNoGreetings when there are no candidates at all
+func (rotator *Rotator) Next(salutation core.Salutation) (string, error) {
+	candidates := rotator.Candidates(salutation)
+	strategy := rotator.Strategy
+	if strategy == nil {
+		strategy = greeting.First
+	}
+
+	rotator.mutex.Lock()
+	defer rotator.mutex.Unlock()
+	var history []string
+	if element, exists := rotator.history[salutation.Name]; exists {
+		history = element.Value.(*nameHistory).greetings
+	}
+	fresh := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if indexOf(history, candidate) < 0 {
+			fresh = append(fresh, candidate)
+		}
+	}
+	var chosen string
+	if len(fresh) == 0 && len(candidates) > 0 {
+		// every candidate was used recently - repeat the one used longest ago
+		chosen = candidates[0]
+		for _, candidate := range candidates[1:] {
+			if indexOf(history, candidate) < indexOf(history, chosen) {
+				chosen = candidate
+			}
+		}
+	} else {
+		var err error
+		if chosen, err = greeting.PickGreeting(salutation.Name, strategy, fresh...); err != nil {
+			return "", err
+		}
+	}
+	rotator.remember(salutation.Name, chosen)
+	return chosen, nil
+}
+
+// Message is Next with the name added - "Good morning, Annica"
+func (rotator *Rotator) Message(salutation core.Salutation) (string, error) {
+	chosen, err := rotator.Next(salutation)
+	if err != nil {
+		return "", err
+	}
+	return chosen + ", " + salutation.Name, nil
+}
+
+// Forget clears the greetings remembered for name
+func (rotator *Rotator) Forget(name string) {
+	rotator.mutex.Lock()
+	defer rotator.mutex.Unlock()
+	if element, exists := rotator.history[name]; exists {
+		rotator.recent.Remove(element)
+		delete(rotator.history, name)
+	}
+}
+
+func (rotator *Rotator) now() time.Time {
+	now := clock.Real.Now()
+	if rotator.Clock != nil {
+		now = rotator.Clock.Now()
+	}
+	if rotator.Location != nil {
+		now = now.In(rotator.Location)
+	}
+	return now
+}
+
+// remember adds a greeting to name's history, keeping only the latest Window of them,
+// and forgets the name greeted longest ago if there are more than MaxNames. The mutex must be held
+func (rotator *Rotator) remember(name, chosen string) {
+	if rotator.Window <= 0 {
+		return
+	}
+	if rotator.history == nil {
+		rotator.history = make(map[string]*list.Element)
+		rotator.recent = list.New()
+	}
+	element, exists := rotator.history[name]
+	if exists {
+		rotator.recent.MoveToFront(element)
+	} else {
+		element = rotator.recent.PushFront(&nameHistory{name: name})
+		rotator.history[name] = element
+	}
+	entry := element.Value.(*nameHistory)
+	history := entry.greetings
+	if i := indexOf(history, chosen); i >= 0 {
+		history = append(history[:i], history[i+1:]...)
+	}
+	history = append(history, chosen)
+	if len(history) > rotator.Window {
+		history = history[len(history)-rotator.Window:]
+	}
+	entry.greetings = history
+
+	maxNames := rotator.MaxNames
+	if maxNames <= 0 {
+		maxNames = DefaultMaxNames
+	}
+	for rotator.recent.Len() > maxNames {
+		oldest := rotator.recent.Back()
+		rotator.recent.Remove(oldest)
+		delete(rotator.history, oldest.Value.(*nameHistory).name)
+	}
+}
+
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,177 @@
+package rotation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/clock"
+	"github.com/annicaburns/learngo/core"
+)
+
+// at returns a fake clock set to the given time on an ordinary day
+func at(hour, minute int) *clock.Fake {
+	return clock.NewFake(time.Date(2026, time.March, 10, hour, minute, 0, 0, time.UTC))
+}
+
+func TestPeriodBoundaries(t *testing.T) {
+	tests := []struct {
+		hour, minute int
+		want         Period
+	}{
+		{0, 0, Night},
+		{4, 59, Night},
+		{5, 0, Morning},
+		{11, 59, Morning},
+		{12, 0, Afternoon},
+		{16, 59, Afternoon},
+		{17, 0, Evening},
+		{21, 59, Evening},
+		{22, 0, Night},
+		{23, 59, Night},
+	}
+	for _, test := range tests {
+		fake := at(test.hour, test.minute)
+		if got := PeriodOf(fake.Now()); got != test.want {
+			t.Errorf("PeriodOf(%02d:%02d) = %v, want %v", test.hour, test.minute, got, test.want)
+		}
+		rotator := &Rotator{Clock: fake}
+		if got, _ := rotator.Next(core.Salutation{Name: "Annica"}); got != DefaultPool[test.want][0] {
+			t.Errorf("Next at %02d:%02d = %q, want %q", test.hour, test.minute, got, DefaultPool[test.want][0])
+		}
+	}
+}
+
+func TestLocationDecidesThePeriod(t *testing.T) {
+	// 10:00 UTC is 19:00 in Tokyo
+	rotator := &Rotator{Clock: at(10, 0), Location: time.FixedZone("JST", 9*60*60)}
+	if got, _ := rotator.Next(core.Salutation{Name: "Annica"}); got != "Good evening" {
+		t.Errorf("Next = %q, want the evening greeting", got)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	rotator := &Rotator{Clock: at(9, 0), Pool: Pool{Morning: {"Good morning", "Hi"}, Anytime: {"Hi", "Hello"}}}
+	got := rotator.Candidates(core.Salutation{Name: "Annica", CasualGreeting: "Howdy"})
+	want := []string{"Good morning", "Hi", "Hello", "Howdy"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Candidates = %q, want %q", got, want)
+	}
+	if _, err := (&Rotator{Clock: at(9, 0), Pool: Pool{}}).Next(core.Salutation{Name: "Annica"}); err == nil {
+		t.Error("Next with no candidates at all didn't fail")
+	}
+}
+
+func TestHolidays(t *testing.T) {
+	calendar, err := ParseCalendar(strings.NewReader("01-01 Happy New Year\n2027-01-01 Happy 2027\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		// the greeting for that single day comes before the one for every year
+		{time.Date(2027, time.January, 1, 9, 0, 0, 0, time.UTC), "Happy 2027"},
+		{time.Date(2028, time.January, 1, 9, 0, 0, 0, time.UTC), "Happy New Year"},
+		{time.Date(2027, time.January, 2, 9, 0, 0, 0, time.UTC), "Good morning"},
+	}
+	for _, test := range tests {
+		rotator := &Rotator{Clock: clock.NewFake(test.date), Calendar: calendar}
+		if got, _ := rotator.Next(core.Salutation{Name: "Annica"}); got != test.want {
+			t.Errorf("Next on %s = %q, want %q", test.date.Format(time.DateOnly), got, test.want)
+		}
+	}
+}
+
+// sequence returns the next n greetings rotator chooses for name
+func sequence(t *testing.T, rotator *Rotator, name string, n int) []string {
+	t.Helper()
+	chosen := make([]string, n)
+	for i := range chosen {
+		var err error
+		if chosen[i], err = rotator.Next(core.Salutation{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return chosen
+}
+
+var threeGreetings = Pool{Anytime: {"A", "B", "C"}}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		window int
+		want   []string
+	}{
+		{0, []string{"A", "A", "A", "A"}},
+		// only the last two are avoided, so A comes back once it drops out of the window
+		{2, []string{"A", "B", "C", "A", "B", "C"}},
+		// every greeting is in the window after three, so the one used longest ago is repeated
+		{5, []string{"A", "B", "C", "A", "B", "C", "A"}},
+	}
+	for _, test := range tests {
+		rotator := &Rotator{Clock: at(9, 0), Pool: threeGreetings, Window: test.window}
+		if got := sequence(t, rotator, "Annica", len(test.want)); !slices.Equal(got, test.want) {
+			t.Errorf("Window %d chose %q, want %q", test.window, got, test.want)
+		}
+	}
+}
+
+func TestEachNameHasItsOwnHistory(t *testing.T) {
+	rotator := &Rotator{Clock: at(9, 0), Pool: threeGreetings, Window: 2}
+	sequence(t, rotator, "Annica", 2)
+	if got := sequence(t, rotator, "Bob", 1)[0]; got != "A" {
+		t.Errorf("Bob's first greeting is %q, want A", got)
+	}
+	rotator.Forget("Annica")
+	if got := sequence(t, rotator, "Annica", 1)[0]; got != "A" {
+		t.Errorf("after Forget Annica got %q, want A", got)
+	}
+}
+
+func TestHistoryKeepsOnlyMaxNames(t *testing.T) {
+	rotator := &Rotator{Clock: at(9, 0), Pool: threeGreetings, Window: 2, MaxNames: 2}
+	sequence(t, rotator, "Annica", 1)
+	sequence(t, rotator, "Bob", 1)
+	sequence(t, rotator, "Annica", 1) // Annica is now greeted more recently than Bob
+	sequence(t, rotator, "Cara", 1)   // so Bob is the one forgotten
+	if got := sequence(t, rotator, "Annica", 1)[0]; got != "C" {
+		t.Errorf("Annica got %q, want C - her history was dropped", got)
+	}
+	if got := sequence(t, rotator, "Bob", 1)[0]; got != "A" {
+		t.Errorf("Bob got %q, want A - his history should have been dropped", got)
+	}
+
+	// however many names come past, no more than MaxNames are kept
+	for i := 0; i < 1000; i++ {
+		sequence(t, rotator, fmt.Sprintf("Person %d", i), 1)
+	}
+	if len(rotator.history) != 2 || rotator.recent.Len() != 2 {
+		t.Errorf("the rotator remembers %d names, want 2", len(rotator.history))
+	}
+}
+
+func TestDefaultMaxNames(t *testing.T) {
+	rotator := &Rotator{Clock: at(9, 0), Pool: threeGreetings, Window: 1}
+	for i := 0; i < DefaultMaxNames+10; i++ {
+		sequence(t, rotator, fmt.Sprintf("Person %d", i), 1)
+	}
+	if len(rotator.history) != DefaultMaxNames {
+		t.Errorf("the rotator remembers %d names, want %d", len(rotator.history), DefaultMaxNames)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	fake := at(11, 59)
+	rotator := &Rotator{Clock: fake}
+	if got, _ := rotator.Message(core.Salutation{Name: "Annica"}); got != "Good morning, Annica" {
+		t.Errorf("Message at 11:59 = %q", got)
+	}
+	fake.Advance(time.Minute)
+	if got, _ := rotator.Message(core.Salutation{Name: "Annica"}); got != "Good afternoon, Annica" {
+		t.Errorf("Message at 12:00 = %q", got)
+	}
+}
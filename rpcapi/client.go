@@ -0,0 +1,162 @@
+package rpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/greeting"
+)
+
+// Errors returned by Client. A failure reported by the Greeter itself is an rpc.ServerError instead
+var (
+	ErrClosed  = errors.New("rpcapi: client is closed")
+	ErrTimeout = errors.New("rpcapi: call timed out")
+)
+
+// ClientOptions configure a Client. Zero values get the defaults below
+type ClientOptions struct {
+	PoolSize    int           // idle connections kept open for reuse - default 4
+	DialTimeout time.Duration // how long to wait for a new connection - default 5s
+	CallTimeout time.Duration // how long to wait for any single call - default 10s
+}
+
+// Client calls a Greeter server, reusing connections from a pool.
+// Each call borrows a connection, or dials a new one if none is idle, and gives it back afterwards.
+// A connection whose call timed out or failed with a network error is closed rather than reused,
+// since it may still deliver the late reply. It is safe to use from several goroutines
+type Client struct {
+	addr    string
+	options ClientOptions
+	idle    chan *rpc.Client
+	mutex   sync.Mutex
+	closed  bool
+}
+
+// NewClient creates a client for the Greeter server at the TCP address addr. Nothing is dialled until the first call
+func NewClient(addr string, options ClientOptions) *Client {
+	if options.PoolSize < 1 {
+		options.PoolSize = 4
+	}
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = 5 * time.Second
+	}
+	if options.CallTimeout <= 0 {
+		options.CallTimeout = 10 * time.Second
+	}
+	return &Client{addr: addr, options: options, idle: make(chan *rpc.Client, options.PoolSize)}
+}
+
+// Greet calls Greeter.Greet
+func (client *Client) Greet(ctx context.Context, salutation greeting.Salutation) (string, error) {
+	var reply GreetReply
+	err := client.call(ctx, "Greet", GreetArgs{Salutation: salutation}, &reply)
+	return reply.Message, err
+}
+
+// IfGreet calls Greeter.IfGreet
+func (client *Client) IfGreet(ctx context.Context, salutation greeting.Salutation, isFormal bool) (string, error) {
+	var reply GreetReply
+	err := client.call(ctx, "IfGreet", IfGreetArgs{Salutation: salutation, IsFormal: isFormal}, &reply)
+	return reply.Message, err
+}
+
+// Rename calls Greeter.Rename
+func (client *Client) Rename(ctx context.Context, salutation goInterfaces.Salutation, newName string) (renamed goInterfaces.Salutation, err error) {
+	err = client.call(ctx, "Rename", RenameArgs{Salutation: salutation, NewName: newName}, &renamed)
+	return
+}
+
+// ListSalutations calls Greeter.ListSalutations
+func (client *Client) ListSalutations(ctx context.Context) (goInterfaces.Salutations, error) {
+	var reply ListReply
+	err := client.call(ctx, "ListSalutations", ListArgs{}, &reply)
+	return reply.Salutations, err
+}
+
+// call makes one call on a pooled connection, giving up when ctx is cancelled or CallTimeout runs out
+func (client *Client) call(ctx context.Context, method string, args, reply interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, client.options.CallTimeout)
+	defer cancel()
+	connection, err := client.get(ctx)
+	if err != nil {
+		return err
+	}
+	call := connection.Go(ServiceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		var serverError rpc.ServerError
+		if call.Error == nil || errors.As(call.Error, &serverError) {
+			// the Greeter answered, so the connection is fine
+			client.put(connection)
+		} else {
+			connection.Close()
+		}
+		return call.Error
+	case <-ctx.Done():
+		connection.Close()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %s after %v", ErrTimeout, method, client.options.CallTimeout)
+		}
+		return ctx.Err()
+	}
+}
+
+// get borrows an idle connection or dials a new one
+func (client *Client) get(ctx context.Context) (*rpc.Client, error) {
+	client.mutex.Lock()
+	closed := client.closed
+	client.mutex.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+	select {
+	case connection := <-client.idle:
+		return connection, nil
+	default:
+	}
+	dialer := net.Dialer{Timeout: client.options.DialTimeout}
+	connection, err := dialer.DialContext(ctx, "tcp", client.addr)
+	if err != nil {
+		return nil, fmt.Errorf("rpcapi: %w", err)
+	}
+	return rpc.NewClient(connection), nil
+}
+
+// put returns a connection to the pool, or closes it if the pool is full or the client is closed
+func (client *Client) put(connection *rpc.Client) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if client.closed {
+		connection.Close()
+		return
+	}
+	select {
+	case client.idle <- connection:
+	default:
+		connection.Close()
+	}
+}
+
+// Close closes every idle connection. Calls still in progress close their connections when they finish
+func (client *Client) Close() error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if client.closed {
+		return ErrClosed
+	}
+	client.closed = true
+	for {
+		select {
+		case connection := <-client.idle:
+			connection.Close()
+		default:
+			return nil
+		}
+	}
+}
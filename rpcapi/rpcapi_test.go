@@ -0,0 +1,174 @@
+package rpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/goMaps"
+	"github.com/annicaburns/learngo/greeting"
+)
+
+// serve runs a Greeter with a registry of its own on 127.0.0.1:0, and returns a client for it.
+// Both are shut down when the test ends
+func serve(t *testing.T) *Client {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	greeter := &Greeter{
+		Prefixes:    goMaps.NewPrefixRegistry(goMaps.DefaultPrefix, map[string]string{"Bob": "Dr "}),
+		Salutations: goInterfaces.VendSalutations,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- Serve(ctx, listener, NewServerFor(greeter)) }()
+	client := NewClient(listener.Addr().String(), ClientOptions{CallTimeout: 5 * time.Second})
+	t.Cleanup(func() {
+		client.Close()
+		cancel()
+		if err := <-served; err != nil {
+			t.Errorf("Serve = %v", err)
+		}
+	})
+	return client
+}
+
+func TestGreet(t *testing.T) {
+	client := serve(t)
+	tests := []struct {
+		salutation greeting.Salutation
+		want       string
+	}{
+		{greeting.Salutation{Name: "Annica", Greeting: "Howdy"}, "Howdy, Annica"},
+		{greeting.Salutation{Name: "Annica"}, "Hey, Annica"},
+		{greeting.Salutation{Name: "Bob", Locale: "es"}, "Hola, Bob"},
+	}
+	for _, test := range tests {
+		message, err := client.Greet(context.Background(), test.salutation)
+		if err != nil || message != test.want {
+			t.Errorf("Greet(%+v) = %q, %v, want %q", test.salutation, message, err, test.want)
+		}
+	}
+}
+
+func TestIfGreet(t *testing.T) {
+	client := serve(t)
+	tests := []struct {
+		salutation greeting.Salutation
+		isFormal   bool
+		want       string
+	}{
+		{greeting.Salutation{Name: "Bob", Greeting: "Howdy"}, false, "Howdy, Bob"},
+		{greeting.Salutation{Name: "Bob", Greeting: "Good evening"}, true, "Good evening, Dr Bob"},
+		{greeting.Salutation{Name: "Bob", Locale: "es"}, true, "Buenos días, Dr Bob"},
+		// a name with no prefix is greeted neutrally rather than with the registry's fallback
+		{greeting.Salutation{Name: "Annica"}, true, "Hello, Annica"},
+	}
+	for _, test := range tests {
+		message, err := client.IfGreet(context.Background(), test.salutation, test.isFormal)
+		if err != nil || message != test.want {
+			t.Errorf("IfGreet(%+v, %v) = %q, %v, want %q", test.salutation, test.isFormal, message, err, test.want)
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	client := serve(t)
+	renamed, err := client.Rename(context.Background(), goInterfaces.Salutation{Name: "Annica", CasualGreeting: "Howdy", FormalGreeting: "Hello"}, "Nic")
+	want := goInterfaces.Salutation{Name: "Nic", CasualGreeting: "Howdy", FormalGreeting: "Hello"}
+	if err != nil || renamed != want {
+		t.Errorf("Rename = %+v, %v, want %+v", renamed, err, want)
+	}
+}
+
+func TestListSalutations(t *testing.T) {
+	client := serve(t)
+	salutations, err := client.ListSalutations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := goInterfaces.VendSalutations()
+	if len(salutations) != len(want) {
+		t.Fatalf("ListSalutations returned %d salutations, want %d", len(salutations), len(want))
+	}
+	for i := range want {
+		if salutations[i] != want[i] {
+			t.Errorf("salutation %d = %+v, want %+v", i, salutations[i], want[i])
+		}
+	}
+}
+
+func TestMissingNameIsAServerError(t *testing.T) {
+	client := serve(t)
+	calls := map[string]func() error{
+		"Greet": func() error {
+			_, err := client.Greet(context.Background(), greeting.Salutation{Greeting: "Howdy"})
+			return err
+		},
+		"IfGreet": func() error {
+			_, err := client.IfGreet(context.Background(), greeting.Salutation{Name: "  "}, true)
+			return err
+		},
+		"Rename": func() error {
+			_, err := client.Rename(context.Background(), goInterfaces.Salutation{Name: "Annica"}, "")
+			return err
+		},
+	}
+	for name, call := range calls {
+		var serverError rpc.ServerError
+		if err := call(); !errors.As(err, &serverError) || string(serverError) != errNoName.Error() {
+			t.Errorf("%s = %v, want the server to report %v", name, err, errNoName)
+		}
+	}
+	// the Greeter answered each time, so the connection is still good for the next call
+	if _, err := client.Greet(context.Background(), greeting.Salutation{Name: "Annica"}); err != nil {
+		t.Errorf("Greet after server errors = %v", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	client := serve(t)
+	if _, err := client.Greet(context.Background(), greeting.Salutation{Name: "Annica"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("the first Close = %v", err)
+	}
+	if err := client.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("the second Close = %v, want ErrClosed", err)
+	}
+	if _, err := client.Greet(context.Background(), greeting.Salutation{Name: "Annica"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Greet after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestServeStopsWithCtx(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- Serve(ctx, listener, NewServer()) }()
+	// an open connection mustn't keep Serve running
+	connection, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer connection.Close()
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Serve = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve didn't return after ctx was cancelled")
+	}
+}
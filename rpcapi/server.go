@@ -0,0 +1,185 @@
+package rpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"strings"
+	"sync"
+
+	"github.com/annicaburns/learngo/goInterfaces"
+	"github.com/annicaburns/learngo/goMaps"
+	"github.com/annicaburns/learngo/greeting"
+)
+
+// rpcapi lets the greeter run as a sidecar that other processes call over TCP, using only net/rpc.
+// net/rpc encodes every call with encoding/gob - a compact binary format - and matches replies to calls,
+// so several calls can share one connection at the same time.
+// The service is registered as "Greeter" and has four methods:
+//
+//	Greeter.Greet            GreetArgs      -> GreetReply     greeting.MessageFor, casually
+//	Greeter.IfGreet          IfGreetArgs    -> GreetReply     greeting.MessageFor, with the name's prefix when formal
+//	Greeter.Rename           RenameArgs     -> goInterfaces.Salutation
+//	Greeter.ListSalutations  ListArgs       -> ListReply      goInterfaces.VendSalutations
+//
+// Client wraps net/rpc's client with a pool of connections, dial and call timeouts, and typed methods.
+
+// ServiceName is the name the Greeter service is registered under
+const ServiceName = "Greeter"
+
+// GreetArgs are the arguments of Greeter.Greet
+type GreetArgs struct {
+	Salutation greeting.Salutation
+}
+
+// IfGreetArgs are the arguments of Greeter.IfGreet
+type IfGreetArgs struct {
+	Salutation greeting.Salutation
+	IsFormal   bool
+}
+
+// GreetReply is the reply of Greeter.Greet and Greeter.IfGreet
+type GreetReply struct {
+	Message string
+}
+
+// RenameArgs are the arguments of Greeter.Rename
+type RenameArgs struct {
+	Salutation goInterfaces.Salutation
+	NewName    string
+}
+
+// ListArgs are the arguments of Greeter.ListSalutations. There aren't any, but net/rpc needs a type
+type ListArgs struct{}
+
+// ListReply is the reply of Greeter.ListSalutations
+type ListReply struct {
+	Salutations goInterfaces.Salutations
+}
+
+// Greeter is the RPC service. Its exported methods follow the shape net/rpc requires:
+// func (t *T) Method(args T1, reply *T2) error
+type Greeter struct {
+	Prefixes    *goMaps.PrefixRegistry
+	Salutations func() goInterfaces.Salutations
+}
+
+// errNoName is returned for a salutation without a name. It reaches the client as an rpc.ServerError
+var errNoName = errors.New("rpcapi: salutation has no name")
+
+// Greet greets the salutation casually, with its own greeting - "Howdy, Annica"
+func (greeter *Greeter) Greet(args GreetArgs, reply *GreetReply) error {
+	return greeter.IfGreet(IfGreetArgs{Salutation: args.Salutation}, reply)
+}
+
+// IfGreet greets the salutation like Greet, or formally with the name's prefix from Prefixes - "Hello, Dr Bob"
+func (greeter *Greeter) IfGreet(args IfGreetArgs, reply *GreetReply) error {
+	if strings.TrimSpace(args.Salutation.Name) == "" {
+		return errNoName
+	}
+	var prefix string
+	if args.IsFormal {
+		prefix, _ = greeter.Prefixes.Lookup(args.Salutation.Name)
+	}
+	message, err := greeting.MessageFor(args.Salutation, prefix, args.IsFormal)
+	if err != nil {
+		return fmt.Errorf("rpcapi: %w", err)
+	}
+	reply.Message = message
+	return nil
+}
+
+// Rename returns the salutation with its name changed
+func (greeter *Greeter) Rename(args RenameArgs, reply *goInterfaces.Salutation) error {
+	if strings.TrimSpace(args.NewName) == "" {
+		return errNoName
+	}
+	*reply = args.Salutation
+	reply.Rename(args.NewName)
+	return nil
+}
+
+// ListSalutations returns every salutation the greeter knows
+func (greeter *Greeter) ListSalutations(args ListArgs, reply *ListReply) error {
+	reply.Salutations = greeter.Salutations()
+	return nil
+}
+
+// NewServer creates an rpc.Server with a Greeter backed by goMaps.Prefixes and goInterfaces.VendSalutations
+func NewServer() *rpc.Server {
+	return NewServerFor(&Greeter{Prefixes: goMaps.Prefixes, Salutations: goInterfaces.VendSalutations})
+}
+
+// NewServerFor creates an rpc.Server for greeter
+func NewServerFor(greeter *Greeter) *rpc.Server {
+	server := rpc.NewServer()
+	if err := server.RegisterName(ServiceName, greeter); err != nil {
+		// only possible if Greeter's methods stop matching what net/rpc requires
+		panic(err)
+	}
+	return server
+}
+
+// Serve accepts connections on listener and serves each one with server until ctx is cancelled.
+// Then it closes the listener and every open connection, and waits for their goroutines to finish
+func Serve(ctx context.Context, listener net.Listener, server *rpc.Server) error {
+	var (
+		mutex       sync.Mutex
+		connections = make(map[net.Conn]struct{})
+		wg          sync.WaitGroup
+	)
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopped:
+		}
+		listener.Close()
+		mutex.Lock()
+		defer mutex.Unlock()
+		for connection := range connections {
+			connection.Close()
+		}
+	}()
+	defer func() {
+		close(stopped)
+		wg.Wait()
+	}()
+
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("rpcapi: %w", err)
+		}
+		mutex.Lock()
+		if ctx.Err() != nil {
+			mutex.Unlock()
+			connection.Close()
+			return nil
+		}
+		connections[connection] = struct{}{}
+		mutex.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.ServeConn(connection)
+			mutex.Lock()
+			delete(connections, connection)
+			mutex.Unlock()
+		}()
+	}
+}
+
+// ListenAndServe listens on the TCP address addr and serves the Greeter until ctx is cancelled
+func ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("rpcapi: %w", err)
+	}
+	return Serve(ctx, listener, NewServer())
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// JSONFileStore keeps its salutations in memory and rewrites the whole file after every change.
+// The file is written to a temporary file first and then renamed, so a crash never leaves half a roster behind.
+// It suits rosters of a few thousand people - use a KVStore when rewriting the whole file gets too slow.
+type JSONFileStore struct {
+	memory *MemoryStore
+	path   string
+}
+
+// OpenJSONFile opens the JSON roster at path. If the file doesn't exist yet it is created by the first change
+func OpenJSONFile(path string) (*JSONFileStore, error) {
+	salutations, err := readJSONFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return &JSONFileStore{memory: NewMemoryStore(salutations...), path: path}, nil
+}
+
+// readJSONFile reads the salutations in the JSON roster at path
+func readJSONFile(path string) (salutations core.Salutations, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	if err := json.Unmarshal(data, &salutations); err != nil {
+		return nil, fmt.Errorf("store: reading %s: %w", path, err)
+	}
+	return salutations, nil
+}
+
+// save writes every salutation to the file. The memory store's lock must be held
+func (store *JSONFileStore) save() error {
+	data, err := json.MarshalIndent(store.memory.list(Query{}), "", "\t")
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(store.path), filepath.Base(store.path)+".*")
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	// CreateTemp makes the file private to its owner - give it the usual permissions of a data file
+	if err := temp.Chmod(0o644); err != nil {
+		temp.Close()
+		return fmt.Errorf("store: writing %s: %w", temp.Name(), err)
+	}
+	if _, err := temp.Write(append(data, '\n')); err != nil {
+		temp.Close()
+		return fmt.Errorf("store: writing %s: %w", temp.Name(), err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("store: writing %s: %w", temp.Name(), err)
+	}
+	if err := os.Rename(temp.Name(), store.path); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	return nil
+}
+
+// Create adds a new salutation and saves the file
+func (store *JSONFileStore) Create(salutation core.Salutation) error {
+	store.memory.mutex.Lock()
+	defer store.memory.mutex.Unlock()
+	if err := store.memory.create(salutation); err != nil {
+		return err
+	}
+	if err := store.save(); err != nil {
+		delete(store.memory.salutations, salutation.Name)
+		return err
+	}
+	return nil
+}
+
+// Get returns the salutation for name
+func (store *JSONFileStore) Get(name string) (core.Salutation, error) {
+	return store.memory.Get(name)
+}
+
+// Update replaces an existing salutation and saves the file
+func (store *JSONFileStore) Update(salutation core.Salutation) error {
+	store.memory.mutex.Lock()
+	defer store.memory.mutex.Unlock()
+	previous, err := store.memory.update(salutation)
+	if err != nil {
+		return err
+	}
+	if err := store.save(); err != nil {
+		store.memory.salutations[previous.Name] = previous
+		return err
+	}
+	return nil
+}
+
+// PutAll creates or replaces every salutation and saves the file once, rather than once for each of them
+func (store *JSONFileStore) PutAll(salutations core.Salutations) error {
+	store.memory.mutex.Lock()
+	defer store.memory.mutex.Unlock()
+	snapshot, err := store.memory.putAll(salutations)
+	if err != nil {
+		return err
+	}
+	if err := store.save(); err != nil {
+		store.memory.salutations = snapshot
+		return err
+	}
+	return nil
+}
+
+// Delete removes the salutation for name and saves the file
+func (store *JSONFileStore) Delete(name string) error {
+	store.memory.mutex.Lock()
+	defer store.memory.mutex.Unlock()
+	previous, err := store.memory.delete(name)
+	if err != nil {
+		return err
+	}
+	if err := store.save(); err != nil {
+		store.memory.salutations[previous.Name] = previous
+		return err
+	}
+	return nil
+}
+
+// List returns the salutations selected by query, sorted by name
+func (store *JSONFileStore) List(query Query) (core.Salutations, error) {
+	return store.memory.List(query)
+}
+
+// Close releases the store. Every change has already been saved
+func (store *JSONFileStore) Close() error {
+	return store.memory.Close()
+}
@@ -0,0 +1,359 @@
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// KVStore is a small embedded key/value file in the style of BoltDB or Bitcask, using only the standard library.
+// Every change is appended to the file as a record and synced to disk, so a change costs one small write
+// no matter how big the roster is. Opening the file replays the records to rebuild the roster in memory.
+//
+// The file starts with kvMagic and is followed by records laid out as
+//
+//	length (4 bytes) | CRC-32 of the payload (4 bytes) | payload
+//
+// and each payload is
+//
+//	operation (1 byte, kvPut or kvDelete) | key length (uvarint) | key | value (JSON, only for kvPut)
+//
+// A record cut short by a crash fails its length or CRC check. If it is the last thing in the file it is truncated
+// on open, but a damaged record with good records after it is reported as ErrCorrupt rather than throwing them away.
+// Updates and deletes leave old records behind, so once most of the file is old records it is compacted:
+// the live records are written to a new file which is then renamed over the old one.
+type KVStore struct {
+	memory   *MemoryStore
+	path     string
+	file     *os.File
+	records  int  // records in the file, including ones later replaced or deleted
+	readOnly bool // opened by LoadRoster - the file is never written, even to truncate or compact it
+}
+
+const (
+	kvMagic          = "LEARNGO-KV-1\n"
+	kvPut            = 'P'
+	kvDelete         = 'D'
+	kvMaxRecord      = 1 << 20 // a longer length can only be a damaged record
+	kvCompactMinimum = 64      // don't bother compacting files smaller than this many records
+)
+
+// ErrCorrupt is returned when opening a key/value file with a damaged record that isn't at the end of the file
+var ErrCorrupt = errors.New("store: damaged record in the middle of the file")
+
+var errTornRecord = errors.New("store: damaged record")
+
+// OpenKV opens the key/value file at path, creating it if it doesn't exist yet
+func OpenKV(path string) (*KVStore, error) {
+	return openKV(path, os.O_RDWR|os.O_CREATE|os.O_APPEND)
+}
+
+// openKV opens the file with flag. With os.O_RDONLY the store is only good for reading
+func openKV(path string, flag int) (*KVStore, error) {
+	file, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	store := &KVStore{memory: NewMemoryStore(), path: path, file: file, readOnly: flag&(os.O_WRONLY|os.O_RDWR) == 0}
+	if err := store.replay(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("store: reading %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// replay reads every record in the file into memory, truncating a damaged tail
+func (store *KVStore) replay() error {
+	info, err := store.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		if store.readOnly {
+			return nil
+		}
+		_, err = store.file.WriteString(kvMagic)
+		return err
+	}
+
+	reader := bufio.NewReader(store.file)
+	magic := make([]byte, len(kvMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != kvMagic {
+		return errors.New("not a learngo key/value file")
+	}
+	offset := int64(len(kvMagic))
+	for {
+		payload, err := readKVRecord(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if errors.Is(err, errTornRecord) {
+			torn, err := store.tornTail(offset, info.Size())
+			switch {
+			case err != nil:
+				return err
+			case !torn:
+				return fmt.Errorf("%w: at byte %d", ErrCorrupt, offset)
+			case store.readOnly:
+				return nil
+			}
+			return store.file.Truncate(offset)
+		}
+		if err != nil {
+			return err
+		}
+		if err := store.apply(payload); err != nil {
+			return err
+		}
+		offset += int64(8 + len(payload))
+		store.records++
+	}
+}
+
+// tornTail reports whether the damaged record at offset is what a crash part way through the last write
+// leaves behind: a record that runs to the end of the file, or nothing but zeros from there on
+func (store *KVStore) tornTail(offset, size int64) (bool, error) {
+	header := make([]byte, 8)
+	if n, _ := store.file.ReadAt(header, offset); n < len(header) {
+		return true, nil
+	}
+	if length := int64(binary.BigEndian.Uint32(header[0:4])); length > 0 && length <= kvMaxRecord && offset+8+length >= size {
+		return true, nil
+	}
+	rest := bufio.NewReader(io.NewSectionReader(store.file, offset, size-offset))
+	for {
+		b, err := rest.ReadByte()
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if b != 0 {
+			return false, nil
+		}
+	}
+}
+
+func readKVRecord(reader io.Reader) (payload []byte, err error) {
+	header := make([]byte, 8)
+	if _, err = io.ReadFull(reader, header); err == io.ErrUnexpectedEOF {
+		return nil, errTornRecord
+	} else if err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length == 0 || length > kvMaxRecord {
+		return nil, errTornRecord
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(reader, payload); err == io.ErrUnexpectedEOF || err == io.EOF {
+		return nil, errTornRecord
+	} else if err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, errTornRecord
+	}
+	return payload, nil
+}
+
+// apply replays a single record into memory
+func (store *KVStore) apply(payload []byte) error {
+	operation := payload[0]
+	keyLength, n := binary.Uvarint(payload[1:])
+	if n <= 0 || uint64(len(payload)-1-n) < keyLength {
+		return errors.New("malformed record")
+	}
+	key := string(payload[1+n : 1+n+int(keyLength)])
+	value := payload[1+n+int(keyLength):]
+	switch operation {
+	case kvPut:
+		var salutation core.Salutation
+		if err := json.Unmarshal(value, &salutation); err != nil {
+			return fmt.Errorf("record for %s: %w", key, err)
+		}
+		store.memory.salutations[key] = salutation
+	case kvDelete:
+		delete(store.memory.salutations, key)
+	default:
+		return fmt.Errorf("unknown record operation %q", operation)
+	}
+	return nil
+}
+
+func encodeKVRecord(operation byte, key string, salutation core.Salutation) ([]byte, error) {
+	payload := []byte{operation}
+	payload = binary.AppendUvarint(payload, uint64(len(key)))
+	payload = append(payload, key...)
+	if operation == kvPut {
+		value, err := json.Marshal(salutation)
+		if err != nil {
+			return nil, err
+		}
+		payload = append(payload, value...)
+	}
+	record := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	return append(record, payload...), nil
+}
+
+// write appends a record and syncs it to disk. The memory store's lock must be held
+func (store *KVStore) write(operation byte, key string, salutation core.Salutation) error {
+	record, err := encodeKVRecord(operation, key, salutation)
+	if err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	info, err := store.file.Stat()
+	if err != nil {
+		return fmt.Errorf("store: writing %s: %w", store.path, err)
+	}
+	if _, err := store.file.Write(record); err != nil {
+		return store.unwrite(info.Size(), err)
+	}
+	if err := store.file.Sync(); err != nil {
+		return store.unwrite(info.Size(), err)
+	}
+	store.records++
+	return nil
+}
+
+// unwrite cuts the file back to size after a failed write, so that a partly written record isn't left
+// at the end of the file for the next record to be written after, where it would be ErrCorrupt on the next open
+func (store *KVStore) unwrite(size int64, err error) error {
+	if truncateErr := store.file.Truncate(size); truncateErr != nil {
+		return fmt.Errorf("store: writing %s: %w - and removing the partly written record: %w", store.path, err, truncateErr)
+	}
+	return fmt.Errorf("store: writing %s: %w", store.path, err)
+}
+
+// compactIfNeeded rewrites the file once more than half of it is old records. The memory store's lock must be held
+func (store *KVStore) compactIfNeeded() error {
+	if store.readOnly || store.records < kvCompactMinimum || store.records <= 2*len(store.memory.salutations) {
+		return nil
+	}
+	return store.compact()
+}
+
+func (store *KVStore) compact() error {
+	temp, err := os.CreateTemp(filepath.Dir(store.path), filepath.Base(store.path)+".compact*")
+	if err != nil {
+		return fmt.Errorf("store: compacting: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if err := temp.Chmod(0o644); err != nil {
+		temp.Close()
+		return fmt.Errorf("store: compacting: %w", err)
+	}
+	writer := bufio.NewWriter(temp)
+	writer.WriteString(kvMagic)
+	for _, salutation := range store.memory.list(Query{}) {
+		record, err := encodeKVRecord(kvPut, salutation.Name, salutation)
+		if err != nil {
+			temp.Close()
+			return fmt.Errorf("store: compacting: %w", err)
+		}
+		writer.Write(record)
+	}
+	if err := writer.Flush(); err != nil {
+		temp.Close()
+		return fmt.Errorf("store: compacting: %w", err)
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return fmt.Errorf("store: compacting: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("store: compacting: %w", err)
+	}
+	if err := os.Rename(temp.Name(), store.path); err != nil {
+		return fmt.Errorf("store: compacting: %w", err)
+	}
+	file, err := os.OpenFile(store.path, os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("store: compacting: %w", err)
+	}
+	store.file.Close()
+	store.file = file
+	store.records = len(store.memory.salutations)
+	return nil
+}
+
+// Create adds a new salutation and appends it to the file
+func (store *KVStore) Create(salutation core.Salutation) error {
+	store.memory.mutex.Lock()
+	defer store.memory.mutex.Unlock()
+	if err := store.memory.create(salutation); err != nil {
+		return err
+	}
+	if err := store.write(kvPut, salutation.Name, salutation); err != nil {
+		delete(store.memory.salutations, salutation.Name)
+		return err
+	}
+	return nil
+}
+
+// Get returns the salutation for name
+func (store *KVStore) Get(name string) (core.Salutation, error) {
+	return store.memory.Get(name)
+}
+
+// Update replaces an existing salutation and appends the new version to the file
+func (store *KVStore) Update(salutation core.Salutation) error {
+	store.memory.mutex.Lock()
+	defer store.memory.mutex.Unlock()
+	previous, err := store.memory.update(salutation)
+	if err != nil {
+		return err
+	}
+	if err := store.write(kvPut, salutation.Name, salutation); err != nil {
+		store.memory.salutations[previous.Name] = previous
+		return err
+	}
+	return store.compactIfNeeded()
+}
+
+// Delete removes the salutation for name and appends a delete record to the file
+func (store *KVStore) Delete(name string) error {
+	store.memory.mutex.Lock()
+	defer store.memory.mutex.Unlock()
+	previous, err := store.memory.delete(name)
+	if err != nil {
+		return err
+	}
+	if err := store.write(kvDelete, name, core.Salutation{}); err != nil {
+		store.memory.salutations[previous.Name] = previous
+		return err
+	}
+	return store.compactIfNeeded()
+}
+
+// List returns the salutations selected by query, sorted by name
+func (store *KVStore) List(query Query) (core.Salutations, error) {
+	return store.memory.List(query)
+}
+
+// Close compacts the file if it needs it and closes it
+func (store *KVStore) Close() error {
+	store.memory.mutex.Lock()
+	if store.memory.closed {
+		store.memory.mutex.Unlock()
+		return nil
+	}
+	err := store.compactIfNeeded()
+	if closeErr := store.file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("store: %w", closeErr)
+	}
+	store.memory.mutex.Unlock()
+	store.memory.Close()
+	return err
+}
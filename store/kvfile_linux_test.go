@@ -0,0 +1,63 @@
+package store
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// limitFileSize stops this process writing files past size until the returned function restores the limit.
+// The Go runtime ignores SIGXFSZ, so a write that crosses the limit is cut short and then fails with EFBIG
+func limitFileSize(t *testing.T, size int64) (restore func()) {
+	t.Helper()
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_FSIZE, &limit); err != nil {
+		t.Skip("can't read the file size limit:", err)
+	}
+	lowered := limit
+	lowered.Cur = uint64(size)
+	if err := syscall.Setrlimit(syscall.RLIMIT_FSIZE, &lowered); err != nil {
+		t.Skip("can't lower the file size limit:", err)
+	}
+	return func() {
+		if err := syscall.Setrlimit(syscall.RLIMIT_FSIZE, &limit); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestKVRemovesAPartlyWrittenRecord(t *testing.T) {
+	path, data := writeKV(t, "Annica", "Bob")
+	store, err := OpenKV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// room for a few bytes of the next record, but not all of it
+	restore := limitFileSize(t, int64(len(data))+5)
+	err = store.Create(core.Salutation{Name: "Mitchel", CasualGreeting: "Hey"})
+	restore()
+	if err == nil {
+		t.Fatal("Create succeeded past the file size limit")
+	}
+	if after, _ := os.ReadFile(path); len(after) != len(data) {
+		t.Errorf("after the failed write the file is %d bytes, want %d", len(after), len(data))
+	}
+	// the store carries on, and the record written next isn't stuck behind a damaged one
+	if err := store.Create(core.Salutation{Name: "Joline"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := OpenKV(path)
+	if err != nil {
+		t.Fatalf("OpenKV after a failed write = %v", err)
+	}
+	defer reopened.Close()
+	salutations, _ := reopened.List(Query{})
+	if _, err := reopened.Get("Mitchel"); len(salutations) != 3 || err == nil {
+		t.Errorf("the reopened store holds %v, want Annica, Bob and Joline", salutations)
+	}
+}
@@ -0,0 +1,258 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+// A SalutationStore keeps a roster of core.Salutations keyed by name, so the demos can work with real people
+// instead of the three fixed salutations from goInterfaces.VendSalutations.
+// There are three implementations:
+//   MemoryStore   - a map guarded by a mutex, gone when the program exits
+//   JSONFileStore - a MemoryStore that rewrites a JSON file after every change
+//   KVStore       - an append-only key/value log file, replayed on open and compacted as it fills with old records
+// Every implementation is safe to use from several goroutines.
+
+// Errors returned by every SalutationStore. Use errors.Is to check for them
+var (
+	ErrNotFound = errors.New("store: salutation not found")
+	ErrExists   = errors.New("store: salutation already exists")
+	ErrNoName   = errors.New("store: salutation has no name")
+	ErrClosed   = errors.New("store: store is closed")
+)
+
+// Query selects salutations from a store. The zero Query selects everything
+type Query struct {
+	NamePrefix string                     // only names starting with this
+	Locale     string                     // only this locale
+	Match      func(core.Salutation) bool // only salutations this returns true for
+	Offset     int                        // skip this many matches
+	Limit      int                        // return at most this many matches - zero means no limit
+}
+
+// matches reports whether a salutation passes every filter in the query
+func (query Query) matches(salutation core.Salutation) bool {
+	if !strings.HasPrefix(salutation.Name, query.NamePrefix) {
+		return false
+	}
+	if query.Locale != "" && !strings.EqualFold(query.Locale, salutation.LocaleOrDefault()) {
+		return false
+	}
+	return query.Match == nil || query.Match(salutation)
+}
+
+// SalutationStore is the interface every store implements. List returns its results sorted by name
+type SalutationStore interface {
+	Create(salutation core.Salutation) error
+	Get(name string) (core.Salutation, error)
+	Update(salutation core.Salutation) error
+	Delete(name string) error
+	List(query Query) (core.Salutations, error)
+	Close() error
+}
+
+// Batcher is implemented by stores that can write many salutations faster all at once than one at a time.
+// PutAll creates each salutation, or replaces it if the name is already there. If it fails, nothing is changed
+type Batcher interface {
+	PutAll(salutations core.Salutations) error
+}
+
+// MemoryStore is a SalutationStore that only lives in memory
+type MemoryStore struct {
+	mutex       sync.RWMutex
+	salutations map[string]core.Salutation
+	closed      bool
+}
+
+// NewMemoryStore creates a MemoryStore holding salutations. A later salutation with the same name replaces an earlier one
+func NewMemoryStore(salutations ...core.Salutation) *MemoryStore {
+	store := &MemoryStore{salutations: make(map[string]core.Salutation, len(salutations))}
+	for _, salutation := range salutations {
+		store.salutations[salutation.Name] = salutation
+	}
+	return store
+}
+
+// Create adds a new salutation
+func (store *MemoryStore) Create(salutation core.Salutation) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.create(salutation)
+}
+
+func (store *MemoryStore) create(salutation core.Salutation) error {
+	if store.closed {
+		return ErrClosed
+	}
+	if salutation.Name == "" {
+		return ErrNoName
+	}
+	if _, exists := store.salutations[salutation.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrExists, salutation.Name)
+	}
+	store.salutations[salutation.Name] = salutation
+	return nil
+}
+
+// Get returns the salutation for name
+func (store *MemoryStore) Get(name string) (core.Salutation, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if store.closed {
+		return core.Salutation{}, ErrClosed
+	}
+	salutation, exists := store.salutations[name]
+	if !exists {
+		return core.Salutation{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return salutation, nil
+}
+
+// Update replaces an existing salutation
+func (store *MemoryStore) Update(salutation core.Salutation) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	_, err := store.update(salutation)
+	return err
+}
+
+// update returns the salutation it replaced so a caller can undo the change
+func (store *MemoryStore) update(salutation core.Salutation) (previous core.Salutation, err error) {
+	if store.closed {
+		return previous, ErrClosed
+	}
+	previous, exists := store.salutations[salutation.Name]
+	if !exists {
+		return previous, fmt.Errorf("%w: %s", ErrNotFound, salutation.Name)
+	}
+	store.salutations[salutation.Name] = salutation
+	return previous, nil
+}
+
+// PutAll creates or replaces every salutation
+func (store *MemoryStore) PutAll(salutations core.Salutations) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	_, err := store.putAll(salutations)
+	return err
+}
+
+// putAll returns the salutations as they were before the change, so a caller can undo it with restore.
+// A snapshot rather than a list of what was replaced, so a name that appears twice is restored to its original
+func (store *MemoryStore) putAll(salutations core.Salutations) (snapshot map[string]core.Salutation, err error) {
+	if store.closed {
+		return nil, ErrClosed
+	}
+	for _, salutation := range salutations {
+		if salutation.Name == "" {
+			return nil, ErrNoName
+		}
+	}
+	snapshot = maps.Clone(store.salutations)
+	for _, salutation := range salutations {
+		store.salutations[salutation.Name] = salutation
+	}
+	return snapshot, nil
+}
+
+// Delete removes the salutation for name
+func (store *MemoryStore) Delete(name string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	_, err := store.delete(name)
+	return err
+}
+
+// delete returns the salutation it removed so a caller can undo the change
+func (store *MemoryStore) delete(name string) (previous core.Salutation, err error) {
+	if store.closed {
+		return previous, ErrClosed
+	}
+	previous, exists := store.salutations[name]
+	if !exists {
+		return previous, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(store.salutations, name)
+	return previous, nil
+}
+
+// List returns the salutations selected by query, sorted by name
+func (store *MemoryStore) List(query Query) (core.Salutations, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if store.closed {
+		return nil, ErrClosed
+	}
+	return store.list(query), nil
+}
+
+func (store *MemoryStore) list(query Query) (result core.Salutations) {
+	names := make([]string, 0, len(store.salutations))
+	for name := range store.salutations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	skipped := 0
+	for _, name := range names {
+		salutation := store.salutations[name]
+		if !query.matches(salutation) {
+			continue
+		}
+		if skipped < query.Offset {
+			skipped++
+			continue
+		}
+		if query.Limit > 0 && len(result) >= query.Limit {
+			break
+		}
+		result = append(result, salutation)
+	}
+	return
+}
+
+// Close empties the store. Any later call returns ErrClosed
+func (store *MemoryStore) Close() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.closed = true
+	store.salutations = nil
+	return nil
+}
+
+// Open opens a file backed store, picking the implementation from the file extension:
+// ".json" opens a JSONFileStore and anything else a KVStore
+func Open(path string) (SalutationStore, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return OpenJSONFile(path)
+	}
+	return OpenKV(path)
+}
+
+// LoadRoster reads every salutation from the store file at path, sorted by name, without changing the file.
+// An empty path means there is no roster, and builtIn is returned instead. A file that doesn't exist is an error
+func LoadRoster(path string, builtIn core.Salutations) (core.Salutations, error) {
+	if path == "" {
+		return builtIn, nil
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		salutations, err := readJSONFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewMemoryStore(salutations...).list(Query{}), nil
+	}
+	store, err := openKV(path, os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	return store.List(Query{})
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/annicaburns/learngo/core"
+)
+
+func TestLoadRosterDoesNotCreateFiles(t *testing.T) {
+	for _, name := range []string{"nope.kv", "nope.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		if _, err := LoadRoster(path, nil); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("LoadRoster(%s) = %v, want fs.ErrNotExist", name, err)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("LoadRoster(%s) left a file behind", name)
+		}
+	}
+}
+
+// writeKV creates a key/value file holding a salutation for each name and returns its path and contents
+func writeKV(t *testing.T, names ...string) (string, []byte) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "roster.kv")
+	store, err := OpenKV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if err := store.Create(core.Salutation{Name: name, CasualGreeting: "Hi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, data
+}
+
+func TestKVTruncatesATornTail(t *testing.T) {
+	tests := map[string]func([]byte) []byte{
+		"cut short":     func(data []byte) []byte { return data[:len(data)-3] },
+		"garbled last":  func(data []byte) []byte { data[len(data)-1] ^= 0xff; return data },
+		"trailing zero": func(data []byte) []byte { return append(data, make([]byte, 20)...) },
+	}
+	for name, damage := range tests {
+		path, data := writeKV(t, "Annica", "Bob", "Mitchel")
+		if err := os.WriteFile(path, damage(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		store, err := OpenKV(path)
+		if err != nil {
+			t.Errorf("%s: OpenKV = %v, want the tail truncated", name, err)
+			continue
+		}
+		salutations, _ := store.List(Query{})
+		store.Close()
+		if name == "trailing zero" && len(salutations) != 3 || name != "trailing zero" && len(salutations) != 2 {
+			t.Errorf("%s: %d salutations survived: %v", name, len(salutations), salutations)
+		}
+	}
+}
+
+func TestKVReportsCorruptionInTheMiddle(t *testing.T) {
+	path, data := writeKV(t, "Annica", "Bob", "Mitchel")
+	// the first record's payload starts after the magic and its 8 byte header
+	data[len(kvMagic)+8+2] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenKV(path); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("OpenKV = %v, want ErrCorrupt", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(data) {
+		t.Errorf("the file was truncated from %d to %d bytes", len(data), len(after))
+	}
+}
+
+func TestJSONPutAllRestoresTheOriginalOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "roster.json")
+	store, err := OpenJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	original := core.Salutation{Name: "Annica", CasualGreeting: "Hi"}
+	if err := store.Create(original); err != nil {
+		t.Fatal(err)
+	}
+	// a directory where the file should be makes saving fail
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	batch := core.Salutations{{Name: "Annica", CasualGreeting: "Hey"}, {Name: "Annica", CasualGreeting: "Yo"}, {Name: "Bob"}}
+	if err := store.PutAll(batch); err == nil {
+		t.Fatal("PutAll succeeded with nowhere to save")
+	}
+	salutations, _ := store.List(Query{})
+	if len(salutations) != 1 || salutations[0] != original {
+		t.Errorf("after a failed PutAll the store holds %v, want only %v", salutations, original)
+	}
+}